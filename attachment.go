@@ -1,38 +1,104 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/webapi"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
 )
 
+// Limits for attachment content returned inline
+const (
+	defaultMaxTextBytes = 100 * 1024
+	maxInlineBinarySize = 5 * 1024 * 1024
+)
+
+// Files larger than the threshold are uploaded in chunks
+const (
+	chunkedUploadThreshold = 8 * 1024 * 1024
+	attachmentChunkSize    = 4 * 1024 * 1024
+)
+
+// Image types that can be returned as MCP image content
+var inlineImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
 // Handler for adding attachment to work item
 func handleAddWorkItemAttachment(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	id := int(request.Params.Arguments["id"].(float64))
-	fileName := request.Params.Arguments["file_name"].(string)
-	content := request.Params.Arguments["content"].(string)
+	comment, _ := request.Params.Arguments["comment"].(string)
 
-	// Decode base64 content
-	fileContent, err := base64.StdEncoding.DecodeString(content)
+	source, size, fileName, closeSource, err := openUploadSource(request)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Invalid base64 content: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
+	defer closeSource()
 
-	// Create upload stream
-	stream := bytes.NewReader(fileContent)
+	// Skip the upload when an attachment with the same name, size and content already exists
+	existing, err := listAttachments(ctx, id, false)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var localHash string
+	for _, attachment := range existing {
+		if !strings.EqualFold(attachment.Name, fileName) || attachment.Size != size || attachment.ID == uuid.Nil {
+			continue
+		}
+		if localHash == "" {
+			if localHash, err = hashContent(source); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to read file: %v", err)), nil
+			}
+			if _, err := source.Seek(0, io.SeekStart); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to read file: %v", err)), nil
+			}
+		}
+		download := true
+		stream, err := workItemClient.GetAttachmentContent(ctx, workitemtracking.GetAttachmentContentArgs{
+			Id:       &attachment.ID,
+			Project:  &config.Project,
+			FileName: &attachment.Name,
+			Download: &download,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to download attachment %s: %v", attachment.Name, err)), nil
+		}
+		remoteHash, err := hashContent(stream)
+		stream.Close()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to download attachment %s: %v", attachment.Name, err)), nil
+		}
+		if remoteHash == localHash {
+			return mcp.NewToolResultText(fmt.Sprintf("Work item #%d already has this file attached as '%s', skipped upload", id, attachment.Name)), nil
+		}
+	}
 
 	// Upload attachment
-	attachment, err := workItemClient.CreateAttachment(ctx, workitemtracking.CreateAttachmentArgs{
-		UploadStream: stream,
-		FileName:     &fileName,
-		Project:      &config.Project,
-	})
+	attachmentURL, err := uploadAttachment(ctx, request, fileName, source, size)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to upload attachment: %v", err)), nil
 	}
@@ -47,9 +113,11 @@ func handleAddWorkItemAttachment(ctx context.Context, request mcp.CallToolReques
 				Path: stringPtr("/relations/-"),
 				Value: map[string]interface{}{
 					"rel": "AttachedFile",
-					"url": *attachment.Url,
+					"url": attachmentURL,
 					"attributes": map[string]interface{}{
-						"name": fileName,
+						"name":         fileName,
+						"comment":      comment,
+						"resourceSize": size,
 					},
 				},
 			},
@@ -61,85 +129,450 @@ func handleAddWorkItemAttachment(ctx context.Context, request mcp.CallToolReques
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to add attachment to work item: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Added attachment '%s' to work item #%d", fileName, id)), nil
+	return mcp.NewToolResultText(fmt.Sprintf("Added attachment '%s' (%d bytes) to work item #%d", fileName, size, id)), nil
 }
 
-// Handler for getting work item attachments
-func handleGetWorkItemAttachments(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	id := int(request.Params.Arguments["id"].(float64))
+// hashContent returns the hex SHA-256 of a stream
+func hashContent(content io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// openUploadSource opens the file to upload from the file_path or base64 content argument
+// and works out its name from file_name or the local path
+func openUploadSource(request mcp.CallToolRequest) (io.ReadSeeker, int64, string, func(), error) {
+	fileName, _ := request.Params.Arguments["file_name"].(string)
+	content, _ := request.Params.Arguments["content"].(string)
+	filePath, _ := request.Params.Arguments["file_path"].(string)
+
+	var source io.ReadSeeker
+	var size int64
+	closeSource := func() {}
+	switch {
+	case filePath != "":
+		resolvedPath, err := resolveUploadPath(filePath)
+		if err != nil {
+			return nil, 0, "", nil, err
+		}
+		file, err := os.Open(resolvedPath)
+		if err != nil {
+			return nil, 0, "", nil, fmt.Errorf("failed to open file: %v", err)
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, 0, "", nil, fmt.Errorf("failed to read file: %v", err)
+		}
+		if fileName == "" {
+			fileName = filepath.Base(resolvedPath)
+		}
+		source, size = file, info.Size()
+		closeSource = func() { file.Close() }
+	case content != "":
+		// Decode base64 content
+		fileContent, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return nil, 0, "", nil, fmt.Errorf("invalid base64 content: %v", err)
+		}
+		source, size = bytes.NewReader(fileContent), int64(len(fileContent))
+	default:
+		return nil, 0, "", nil, fmt.Errorf("either content or file_path is required")
+	}
+	if fileName == "" {
+		closeSource()
+		return nil, 0, "", nil, fmt.Errorf("file_name is required when uploading base64 content")
+	}
+	return source, size, fileName, closeSource, nil
+}
+
+// resolveUploadPath checks that a local path lies inside the configured upload directory
+func resolveUploadPath(path string) (string, error) {
+	if config.UploadDirectory == "" {
+		return "", fmt.Errorf("uploading from local paths is disabled (set AZURE_DEVOPS_UPLOAD_DIR)")
+	}
+
+	root, err := filepath.EvalSymlinks(config.UploadDirectory)
+	if err != nil {
+		return "", fmt.Errorf("invalid upload directory: %v", err)
+	}
+	root, _ = filepath.Abs(root)
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("file not found: %s", path)
+	}
+	resolved, _ = filepath.Abs(resolved)
 
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the allowed upload directory", path)
+	}
+	return resolved, nil
+}
+
+// resolveDownloadPath resolves a path below the download directory, which need not exist yet
+func resolveDownloadPath(path string) (string, error) {
+	if config.DownloadDirectory == "" {
+		return "", fmt.Errorf("no download directory configured (set AZURE_DEVOPS_DOWNLOAD_DIR)")
+	}
+
+	root, err := filepath.Abs(config.DownloadDirectory)
+	if err != nil {
+		return "", fmt.Errorf("invalid download directory: %v", err)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	resolved := filepath.Clean(path)
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the download directory", path)
+	}
+	return resolved, nil
+}
+
+// sendProgress reports upload progress to the client if it asked for progress notifications
+func sendProgress(ctx context.Context, request mcp.CallToolRequest, progress, total int64) {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return
+	}
+	_ = srv.SendNotificationToClient("notifications/progress", map[string]interface{}{
+		"progressToken": request.Params.Meta.ProgressToken,
+		"progress":      progress,
+		"total":         total,
+	})
+}
+
+// uploadAttachment uploads content to the attachment store, using chunks for large files,
+// and returns the attachment URL
+func uploadAttachment(ctx context.Context, request mcp.CallToolRequest, fileName string, content io.Reader, size int64) (string, error) {
+	if size <= chunkedUploadThreshold {
+		attachment, err := workItemClient.CreateAttachment(ctx, workitemtracking.CreateAttachmentArgs{
+			UploadStream: content,
+			FileName:     &fileName,
+			Project:      &config.Project,
+		})
+		if err != nil {
+			return "", err
+		}
+		sendProgress(ctx, request, size, size)
+		return *attachment.Url, nil
+	}
+
+	// Start a chunked upload
+	startURL := projectAPIURL("wit/attachments?fileName=%s&uploadType=Chunked&api-version=7.1", url.QueryEscape(fileName))
+	var attachment struct {
+		Id  string `json:"id"`
+		Url string `json:"url"`
+	}
+	if err := doAzureDevOpsJSON(ctx, http.MethodPost, startURL, nil, &attachment); err != nil {
+		return "", fmt.Errorf("failed to start chunked upload: %v", err)
+	}
+
+	chunkURL := projectAPIURL("wit/attachments/%s?fileName=%s&uploadType=Chunked&api-version=7.1", attachment.Id, url.QueryEscape(fileName))
+	buffer := make([]byte, attachmentChunkSize)
+	var offset int64
+	for offset < size {
+		n, err := io.ReadFull(content, buffer)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return "", fmt.Errorf("failed to read file: %v", err)
+		}
+
+		resp, err := sendAzureDevOpsRequest(ctx, http.MethodPut, chunkURL, bytes.NewReader(buffer[:n]), map[string]string{
+			"Content-Type":  "application/octet-stream",
+			"Content-Range": fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(n)-1, size),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to upload chunk: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return "", fmt.Errorf("failed to upload chunk at offset %d: status %d: %s", offset, resp.StatusCode, apiErrorMessage(body))
+		}
+
+		offset += int64(n)
+		sendProgress(ctx, request, offset, size)
+	}
+
+	return attachment.Url, nil
+}
+
+// attachmentInfo describes an AttachedFile relation on a work item
+type attachmentInfo struct {
+	Index      int // Position in the work item's relations, used for removal
+	ID         uuid.UUID
+	Name       string
+	Size       int64
+	Comment    string
+	URL        string
+	UploadedBy string
+	Date       time.Time
+}
+
+// attachmentIDFromURL extracts the attachment GUID from an attachment URL
+func attachmentIDFromURL(attachmentURL string) (uuid.UUID, bool) {
+	path := strings.SplitN(attachmentURL, "?", 2)[0]
+	parts := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	id, err := uuid.Parse(parts[len(parts)-1])
+	return id, err == nil
+}
+
+// listAttachments returns the attachments of a work item. With withUploader set, the
+// work item history is read to find who added each attachment.
+func listAttachments(ctx context.Context, workItemID int, withUploader bool) ([]attachmentInfo, error) {
 	workItem, err := workItemClient.GetWorkItem(ctx, workitemtracking.GetWorkItemArgs{
-		Id:      &id,
+		Id:      &workItemID,
 		Project: &config.Project,
 		Expand:  &workitemtracking.WorkItemExpandValues.Relations,
 	})
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get work item: %v", err)), nil
+		return nil, fmt.Errorf("failed to get work item: %v", err)
 	}
-
 	if workItem.Relations == nil {
-		return mcp.NewToolResultText(fmt.Sprintf("No attachments found for work item #%d", id)), nil
+		return nil, nil
 	}
 
-	var results []string
-	for _, relation := range *workItem.Relations {
-		if *relation.Rel == "AttachedFile" {
-			name := (*relation.Attributes)["name"].(string)
-			results = append(results, fmt.Sprintf("ID: %s\nName: %s\nURL: %s\n---",
-				*relation.Url,
-				name,
-				*relation.Url))
+	var attachments []attachmentInfo
+	for i, relation := range *workItem.Relations {
+		if relation.Rel == nil || *relation.Rel != "AttachedFile" || relation.Url == nil {
+			continue
+		}
+		info := attachmentInfo{Index: i, URL: *relation.Url}
+		info.ID, _ = attachmentIDFromURL(*relation.Url)
+
+		if relation.Attributes != nil {
+			attributes := *relation.Attributes
+			info.Name, _ = attributes["name"].(string)
+			info.Comment, _ = attributes["comment"].(string)
+			if size, ok := attributes["resourceSize"].(float64); ok {
+				info.Size = int64(size)
+			}
+			for _, key := range []string{"authorizedDate", "resourceCreatedDate"} {
+				if date, ok := attributes[key].(string); ok {
+					if t, err := time.Parse(time.RFC3339, date); err == nil {
+						info.Date = t
+						break
+					}
+				}
+			}
+		}
+		attachments = append(attachments, info)
+	}
+
+	if withUploader && len(attachments) > 0 {
+		updates, err := getAllWorkItemUpdates(ctx, workItemID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get work item history: %v", err)
+		}
+		for _, update := range updates {
+			if update.Relations == nil || update.Relations.Added == nil || update.RevisedBy == nil || update.RevisedBy.DisplayName == nil {
+				continue
+			}
+			for _, added := range *update.Relations.Added {
+				if added.Url == nil {
+					continue
+				}
+				for i := range attachments {
+					if strings.EqualFold(attachments[i].URL, *added.Url) {
+						attachments[i].UploadedBy = *update.RevisedBy.DisplayName
+					}
+				}
+			}
+		}
+	}
+
+	return attachments, nil
+}
+
+// matchAttachment picks the single attachment whose GUID or file name equals ref
+func matchAttachment(attachments []attachmentInfo, ref string) (attachmentInfo, error) {
+	var byName []attachmentInfo
+	for _, attachment := range attachments {
+		if strings.EqualFold(attachment.ID.String(), ref) {
+			return attachment, nil
+		}
+		if attachment.Name == ref {
+			byName = append(byName, attachment)
+		}
+	}
+
+	// Fall back to a case-insensitive file name match
+	if len(byName) == 0 {
+		for _, attachment := range attachments {
+			if strings.EqualFold(attachment.Name, ref) {
+				byName = append(byName, attachment)
+			}
+		}
+	}
+
+	switch len(byName) {
+	case 0:
+		return attachmentInfo{}, fmt.Errorf("attachment %s not found", ref)
+	case 1:
+		return byName[0], nil
+	}
+
+	var candidates []string
+	for _, attachment := range byName {
+		candidates = append(candidates, fmt.Sprintf("%s (%d bytes, %s)", attachment.ID, attachment.Size, attachment.Date.Format("2006-01-02")))
+	}
+	return attachmentInfo{}, fmt.Errorf("several attachments are named %s, use one of these IDs: %s", ref, strings.Join(candidates, ", "))
+}
+
+// resolveAttachment finds an attachment by GUID, URL or (with a work item ID) file name
+func resolveAttachment(ctx context.Context, workItemID int, ref string) (uuid.UUID, string, error) {
+	if id, err := uuid.Parse(ref); err == nil && workItemID == 0 {
+		return id, "", nil
+	}
+	if strings.Contains(ref, "/") {
+		if id, ok := attachmentIDFromURL(ref); ok {
+			return id, "", nil
 		}
+		return uuid.Nil, "", fmt.Errorf("invalid attachment URL: %s", ref)
+	}
+	if workItemID == 0 {
+		return uuid.Nil, "", fmt.Errorf("work item id is required to find an attachment by file name")
+	}
+
+	attachments, err := listAttachments(ctx, workItemID, false)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	attachment, err := matchAttachment(attachments, ref)
+	if err != nil {
+		return uuid.Nil, "", fmt.Errorf("%v on work item #%d", err, workItemID)
+	}
+	return attachment.ID, attachment.Name, nil
+}
+
+// Handler for getting work item attachments
+func handleGetWorkItemAttachments(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := int(request.Params.Arguments["id"].(float64))
+
+	attachments, err := listAttachments(ctx, id, true)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	if len(results) == 0 {
+	if len(attachments) == 0 {
 		return mcp.NewToolResultText(fmt.Sprintf("No attachments found for work item #%d", id)), nil
 	}
 
+	var results []string
+	for _, attachment := range attachments {
+		uploadedBy := attachment.UploadedBy
+		if uploadedBy == "" {
+			uploadedBy = "Unknown"
+		}
+		result := fmt.Sprintf("ID: %s\nName: %s\nSize: %d bytes\nUploaded by: %s\nDate: %s\nURL: %s",
+			attachment.ID,
+			attachment.Name,
+			attachment.Size,
+			uploadedBy,
+			attachment.Date.Format(time.RFC3339),
+			attachment.URL)
+		if attachment.Comment != "" {
+			result += fmt.Sprintf("\nComment: %s", attachment.Comment)
+		}
+		results = append(results, result+"\n---")
+	}
+
 	return mcp.NewToolResultText(strings.Join(results, "\n")), nil
 }
 
-// Handler for removing attachment from work item
+// Handler for removing attachments from work item
 func handleRemoveWorkItemAttachment(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	id := int(request.Params.Arguments["id"].(float64))
-	attachmentID := request.Params.Arguments["attachment_id"].(string)
+	attachmentID, _ := request.Params.Arguments["attachment_id"].(string)
+	namePattern, _ := request.Params.Arguments["name_pattern"].(string)
+	olderThanStr, _ := request.Params.Arguments["older_than"].(string)
+	confirm, _ := request.Params.Arguments["confirm"].(bool)
 
-	workItem, err := workItemClient.GetWorkItem(ctx, workitemtracking.GetWorkItemArgs{
-		Id:      &id,
-		Project: &config.Project,
-		Expand:  &workitemtracking.WorkItemExpandValues.Relations,
-	})
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get work item: %v", err)), nil
+	if attachmentID == "" && namePattern == "" && olderThanStr == "" {
+		return mcp.NewToolResultError("Specify attachment_id, or name_pattern and/or older_than for bulk removal"), nil
 	}
 
-	if workItem.Relations == nil {
+	attachments, err := listAttachments(ctx, id, false)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(attachments) == 0 {
 		return mcp.NewToolResultError("Work item has no attachments"), nil
 	}
 
-	// Find the attachment relation index
-	var relationIndex int = -1
-	for i, relation := range *workItem.Relations {
-		if *relation.Rel == "AttachedFile" && strings.Contains(*relation.Url, attachmentID) {
-			relationIndex = i
-			break
+	var toRemove []attachmentInfo
+	if attachmentID != "" {
+		attachment, err := matchAttachment(attachments, attachmentID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		toRemove = append(toRemove, attachment)
+	} else {
+		var olderThan time.Time
+		if olderThanStr != "" {
+			if olderThan, err = parseDateArg(olderThanStr); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		for _, attachment := range attachments {
+			if namePattern != "" {
+				matched, err := filepath.Match(strings.ToLower(namePattern), strings.ToLower(attachment.Name))
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("Invalid name_pattern: %v", err)), nil
+				}
+				if !matched {
+					continue
+				}
+			}
+			if !olderThan.IsZero() && (attachment.Date.IsZero() || !attachment.Date.Before(olderThan)) {
+				continue
+			}
+			toRemove = append(toRemove, attachment)
+		}
+
+		if len(toRemove) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf("No attachments on work item #%d match the given filters", id)), nil
+		}
+
+		// Bulk removal needs explicit confirmation
+		if !confirm {
+			var names []string
+			for _, attachment := range toRemove {
+				names = append(names, fmt.Sprintf("- %s (%s, %s)", attachment.Name, attachment.ID, attachment.Date.Format("2006-01-02")))
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("%d attachments would be removed from work item #%d:\n%s\n\nCall again with confirm=true to remove them.",
+				len(toRemove), id, strings.Join(names, "\n"))), nil
 		}
 	}
 
-	if relationIndex == -1 {
-		return mcp.NewToolResultError("Attachment not found"), nil
+	// Remove from the highest index down so earlier removals don't shift later ones
+	sort.Slice(toRemove, func(i, j int) bool { return toRemove[i].Index > toRemove[j].Index })
+	var ops []webapi.JsonPatchOperation
+	var names []string
+	for _, attachment := range toRemove {
+		ops = append(ops, webapi.JsonPatchOperation{
+			Op:   &webapi.OperationValues.Remove,
+			Path: stringPtr(fmt.Sprintf("/relations/%d", attachment.Index)),
+		})
+		names = append(names, attachment.Name)
 	}
 
-	// Remove the attachment relation
 	updateArgs := workitemtracking.UpdateWorkItemArgs{
-		Id:      &id,
-		Project: &config.Project,
-		Document: &[]webapi.JsonPatchOperation{
-			{
-				Op:   &webapi.OperationValues.Remove,
-				Path: stringPtr(fmt.Sprintf("/relations/%d", relationIndex)),
-			},
-		},
+		Id:       &id,
+		Project:  &config.Project,
+		Document: &ops,
 	}
 
 	_, err = workItemClient.UpdateWorkItem(ctx, updateArgs)
@@ -147,5 +580,188 @@ func handleRemoveWorkItemAttachment(ctx context.Context, request mcp.CallToolReq
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to remove attachment: %v", err)), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Removed attachment from work item #%d", id)), nil
+	return mcp.NewToolResultText(fmt.Sprintf("Removed %d attachment(s) from work item #%d: %s", len(toRemove), id, strings.Join(names, ", "))), nil
+}
+
+// decodeText detects the text encoding of data, returning false for binary content
+func decodeText(data []byte) (string, string, bool) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return string(data[3:]), "UTF-8 (BOM)", true
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		bigEndian := data[0] == 0xFE
+		units := make([]uint16, 0, len(data)/2)
+		for i := 2; i+1 < len(data); i += 2 {
+			if bigEndian {
+				units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+			} else {
+				units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+			}
+		}
+		encoding := "UTF-16LE"
+		if bigEndian {
+			encoding = "UTF-16BE"
+		}
+		return string(utf16.Decode(units)), encoding, true
+	}
+
+	if bytes.IndexByte(data, 0) != -1 {
+		return "", "", false
+	}
+
+	// Allow a multi-byte character cut off by the size cap
+	valid := data
+	for i := 0; i < 3 && len(valid) > 0 && !utf8.Valid(valid); i++ {
+		valid = valid[:len(valid)-1]
+	}
+	if utf8.Valid(valid) {
+		return string(valid), "UTF-8", true
+	}
+
+	// Fall back to Latin-1 when the content is mostly printable
+	printable := 0
+	for _, b := range data {
+		if b >= 0x20 || b == '\n' || b == '\r' || b == '\t' {
+			printable++
+		}
+	}
+	if printable*100/max(len(data), 1) < 95 {
+		return "", "", false
+	}
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes), "ISO-8859-1", true
+}
+
+// saveAttachment streams content into the download directory without overwriting existing files
+func saveAttachment(fileName string, content io.Reader) (string, int64, error) {
+	if config.DownloadDirectory == "" {
+		return "", 0, fmt.Errorf("no download directory configured (set AZURE_DEVOPS_DOWNLOAD_DIR)")
+	}
+	if err := os.MkdirAll(config.DownloadDirectory, 0o755); err != nil {
+		return "", 0, fmt.Errorf("failed to create download directory: %v", err)
+	}
+
+	base := filepath.Base(fileName)
+	if base == "." || base == string(filepath.Separator) {
+		base = "attachment"
+	}
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+
+	// Create the file exclusively so concurrent downloads of the same name never share a file
+	path := filepath.Join(config.DownloadDirectory, base)
+	var file *os.File
+	for i := 1; ; i++ {
+		var err error
+		file, err = os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", 0, fmt.Errorf("failed to create file: %v", err)
+		}
+		path = filepath.Join(config.DownloadDirectory, fmt.Sprintf("%s (%d)%s", stem, i, ext))
+	}
+	defer file.Close()
+
+	written, err := io.Copy(file, content)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to write file: %v", err)
+	}
+	return path, written, nil
+}
+
+// Handler for getting the content of an attachment
+func handleGetAttachmentContent(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ref := request.Params.Arguments["attachment"].(string)
+	workItemID := 0
+	if id, ok := request.Params.Arguments["id"].(float64); ok {
+		workItemID = int(id)
+	}
+	save, _ := request.Params.Arguments["save"].(bool)
+	maxBytes := defaultMaxTextBytes
+	if limit, ok := request.Params.Arguments["max_bytes"].(float64); ok && limit > 0 {
+		maxBytes = int(limit)
+	}
+
+	attachmentID, fileName, err := resolveAttachment(ctx, workItemID, ref)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if fileName == "" {
+		fileName = attachmentID.String()
+	}
+
+	download := true
+	stream, err := workItemClient.GetAttachmentContent(ctx, workitemtracking.GetAttachmentContentArgs{
+		Id:       &attachmentID,
+		Project:  &config.Project,
+		FileName: &fileName,
+		Download: &download,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to download attachment: %v", err)), nil
+	}
+	defer stream.Close()
+
+	return attachmentContentResult(fileName, stream, save, maxBytes), nil
+}
+
+// attachmentContentResult returns a downloaded file as text, image content or base64, or saves
+// it to the download directory when asked to or when it is a binary that is not an image
+func attachmentContentResult(fileName string, stream io.Reader, save bool, maxBytes int) *mcp.CallToolResult {
+	reader := bufio.NewReader(stream)
+	head, _ := reader.Peek(512)
+	contentType := imageContentType(fileName, head)
+
+	_, _, headIsText := decodeText(head)
+	isImage := inlineImageTypes[contentType]
+
+	// Binaries that are not images go to disk when a download directory is configured
+	if save || (!headIsText && !isImage && config.DownloadDirectory != "") {
+		path, size, err := saveAttachment(fileName, reader)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to save attachment: %v", err))
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Saved attachment '%s' (%s, %d bytes) to %s", fileName, contentType, size, path))
+	}
+
+	if headIsText && !isImage {
+		data, err := io.ReadAll(io.LimitReader(reader, int64(maxBytes)+1))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read attachment: %v", err))
+		}
+		full := data
+		truncated := len(data) > maxBytes
+		if truncated {
+			data = data[:maxBytes]
+		}
+		if text, encoding, ok := decodeText(data); ok {
+			header := fmt.Sprintf("=== %s (%s, %s) ===\n", fileName, contentType, encoding)
+			if truncated {
+				text += fmt.Sprintf("\n\n[Truncated after %d bytes - raise max_bytes or use save to get the full file]", maxBytes)
+			}
+			return mcp.NewToolResultText(header + text)
+		}
+		// Not text after all, so fall through to binary handling with what was read
+		reader = bufio.NewReader(io.MultiReader(bytes.NewReader(full), reader))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxInlineBinarySize+1))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read attachment: %v", err))
+	}
+	if len(data) > maxInlineBinarySize {
+		return mcp.NewToolResultError(fmt.Sprintf("Attachment '%s' is larger than %d bytes; configure AZURE_DEVOPS_DOWNLOAD_DIR and use save to download it", fileName, maxInlineBinarySize))
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+	if isImage {
+		return mcp.NewToolResultImage(fmt.Sprintf("Attachment '%s' (%s, %d bytes)", fileName, contentType, len(data)), encoded, contentType)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Attachment '%s' (%s, %d bytes), base64 encoded:\n%s", fileName, contentType, len(data), encoded))
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		want     string
+		encoding string
+		ok       bool
+	}{
+		{"utf-8", []byte("héllo"), "héllo", "UTF-8", true},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFhi"), "hi", "UTF-8 (BOM)", true},
+		{"utf-16le", []byte{0xFF, 0xFE, 'h', 0, 'i', 0}, "hi", "UTF-16LE", true},
+		{"cut multi-byte character", []byte("ab\xC3"), "ab", "UTF-8", true},
+		{"nul bytes", []byte{'a', 0, 'b'}, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, encoding, ok := decodeText(tt.data)
+			if ok != tt.ok || got != tt.want || encoding != tt.encoding {
+				t.Errorf("decodeText(%q) = %q, %q, %v, want %q, %q, %v", tt.data, got, encoding, ok, tt.want, tt.encoding, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAttachmentContentResultBinaryAfterTextHead(t *testing.T) {
+	// The first 512 bytes look like text, but a NUL byte before max_bytes makes the file binary
+	content := append(bytes.Repeat([]byte("a"), 600), 0)
+	content = append(content, bytes.Repeat([]byte("b"), 200)...)
+
+	for _, maxBytes := range []int{700, 2000} {
+		result := attachmentContentResult("data.bin", bytes.NewReader(content), false, maxBytes)
+		if result.IsError {
+			t.Fatalf("max_bytes %d: unexpected error result", maxBytes)
+		}
+		text := result.Content[0].(mcp.TextContent).Text
+		i := strings.Index(text, "base64 encoded:\n")
+		if i == -1 {
+			t.Fatalf("max_bytes %d: expected base64 output, got %q", maxBytes, text[:min(len(text), 80)])
+		}
+		decoded, err := base64.StdEncoding.DecodeString(text[i+len("base64 encoded:\n"):])
+		if err != nil || !bytes.Equal(decoded, content) {
+			t.Errorf("max_bytes %d: base64 output does not match the attachment (%d of %d bytes)", maxBytes, len(decoded), len(content))
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+)
+
+// API version of the work item comments endpoints
+const commentsAPIVersion = "7.1-preview.4"
+
+// commentResponse is the subset of a comment returned by the comments endpoints
+type commentResponse struct {
+	Id         int    `json:"id"`
+	WorkItemId int    `json:"workItemId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+// commentFormatArg reads the optional format argument, defaulting to markdown
+func commentFormatArg(request mcp.CallToolRequest) string {
+	format, _ := request.Params.Arguments["format"].(string)
+	if format == "" {
+		format = "markdown"
+	}
+	return format
+}
+
+// identityName returns the display name of an identity, tolerating missing values
+func identityName(identity *webapi.IdentityRef) string {
+	if identity == nil || identity.DisplayName == nil {
+		return "Unknown"
+	}
+	return *identity.DisplayName
+}
+
+// postWorkItemComment adds a comment in the given format (markdown or html)
+func postWorkItemComment(ctx context.Context, id int, text, format string) (commentResponse, error) {
+	// The SDK does not expose the format parameter, so call the comments endpoint directly
+	commentURL := projectAPIURL("wit/workItems/%d/comments?format=%s&api-version=%s", id, format, commentsAPIVersion)
+
+	var comment commentResponse
+	err := doAzureDevOpsJSON(ctx, http.MethodPost, commentURL, map[string]string{"text": text}, &comment)
+	return comment, err
+}
+
+// Handler for adding a comment to a work item
+func handleAddWorkItemComment(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := int(request.Params.Arguments["id"].(float64))
+	text := request.Params.Arguments["text"].(string)
+	format := commentFormatArg(request)
+	text = convertMentions(ctx, text, format)
+	if format == "html" {
+		text = sanitizeHTML(text)
+	}
+
+	comment, err := postWorkItemComment(ctx, id, text, format)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to add comment: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Added comment %d to work item #%d", comment.Id, id)), nil
+}
+
+// Handler for editing a work item comment
+func handleUpdateWorkItemComment(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := int(request.Params.Arguments["id"].(float64))
+	commentID := int(request.Params.Arguments["comment_id"].(float64))
+	text := request.Params.Arguments["text"].(string)
+	format := commentFormatArg(request)
+	text = convertMentions(ctx, text, format)
+	if format == "html" {
+		text = sanitizeHTML(text)
+	}
+
+	commentURL := projectAPIURL("wit/workItems/%d/comments/%d?format=%s&api-version=%s", id, commentID, format, commentsAPIVersion)
+
+	var comment commentResponse
+	if err := doAzureDevOpsJSON(ctx, http.MethodPatch, commentURL, map[string]string{"text": text}, &comment); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to update comment: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Updated comment %d on work item #%d (version %d)", commentID, id, comment.Version)), nil
+}
+
+// Handler for deleting a work item comment
+func handleDeleteWorkItemComment(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := int(request.Params.Arguments["id"].(float64))
+	commentID := int(request.Params.Arguments["comment_id"].(float64))
+
+	err := workItemClient.DeleteComment(ctx, workitemtracking.DeleteCommentArgs{
+		Project:    &config.Project,
+		WorkItemId: &id,
+		CommentId:  &commentID,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete comment: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Deleted comment %d from work item #%d", commentID, id)), nil
+}
+
+// Handler for adding or removing a comment reaction
+func handleManageCommentReaction(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := int(request.Params.Arguments["id"].(float64))
+	commentID := int(request.Params.Arguments["comment_id"].(float64))
+	reaction := workitemtracking.CommentReactionType(request.Params.Arguments["reaction"].(string))
+	operation := request.Params.Arguments["operation"].(string)
+
+	var err error
+	if operation == "add" {
+		_, err = workItemClient.CreateCommentReaction(ctx, workitemtracking.CreateCommentReactionArgs{
+			Project:      &config.Project,
+			WorkItemId:   &id,
+			CommentId:    &commentID,
+			ReactionType: &reaction,
+		})
+	} else {
+		_, err = workItemClient.DeleteCommentReaction(ctx, workitemtracking.DeleteCommentReactionArgs{
+			Project:      &config.Project,
+			WorkItemId:   &id,
+			CommentId:    &commentID,
+			ReactionType: &reaction,
+		})
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to %s reaction: %v", operation, err)), nil
+	}
+
+	verb := "Added"
+	if operation != "add" {
+		verb = "Removed"
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("%s %s reaction on comment %d", verb, reaction, commentID)), nil
+}
+
+// Handler for getting work item comments
+func handleGetWorkItemComments(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := int(request.Params.Arguments["id"].(float64))
+	pageSize := 50
+	if size, ok := request.Params.Arguments["page_size"].(float64); ok && size > 0 {
+		pageSize = int(size)
+	}
+	includeDeleted, _ := request.Params.Arguments["include_deleted"].(bool)
+	order := workitemtracking.CommentSortOrderValues.Asc
+	if o, _ := request.Params.Arguments["order"].(string); o == "desc" {
+		order = workitemtracking.CommentSortOrderValues.Desc
+	}
+
+	// Markdown comments only carry HTML, and so their image tags, in the rendered text
+	includeImages, _ := request.Params.Arguments["include_images"].(bool)
+	expand := workitemtracking.CommentExpandOptionsValues.Reactions
+	if includeImages {
+		expand = workitemtracking.CommentExpandOptionsValues.All
+	}
+
+	args := workitemtracking.GetCommentsArgs{
+		Project:        &config.Project,
+		WorkItemId:     &id,
+		Top:            &pageSize,
+		IncludeDeleted: &includeDeleted,
+		Expand:         &expand,
+		Order:          &order,
+	}
+	if token, _ := request.Params.Arguments["continuation_token"].(string); token != "" {
+		args.ContinuationToken = &token
+	}
+
+	comments, err := workItemClient.GetComments(ctx, args)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get comments: %v", err)), nil
+	}
+
+	if comments.Comments == nil || len(*comments.Comments) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No comments found for work item #%d", id)), nil
+	}
+
+	var results []string
+	var commentHTML []string
+	if comments.TotalCount != nil {
+		results = append(results, fmt.Sprintf("Showing %d of %d comments:\n", len(*comments.Comments), *comments.TotalCount))
+	}
+
+	for _, comment := range *comments.Comments {
+		commentID := 0
+		if comment.Id != nil {
+			commentID = *comment.Id
+		}
+		createdDate := ""
+		if comment.CreatedDate != nil {
+			createdDate = comment.CreatedDate.Time.Format(time.RFC3339)
+		}
+		text := ""
+		if comment.Text != nil {
+			text = *comment.Text
+		}
+		if comment.Format != nil && *comment.Format == workitemtracking.CommentFormatValues.Html {
+			commentHTML = append(commentHTML, text)
+			text = htmlToMarkdown(text)
+		} else if comment.RenderedText != nil {
+			commentHTML = append(commentHTML, *comment.RenderedText)
+		}
+
+		header := fmt.Sprintf("Comment %d by %s at %s", commentID, identityName(comment.CreatedBy), createdDate)
+		if comment.ModifiedDate != nil && comment.CreatedDate != nil && comment.ModifiedDate.Time.After(comment.CreatedDate.Time) {
+			header += fmt.Sprintf(" (edited %s)", comment.ModifiedDate.Time.Format(time.RFC3339))
+		}
+		if comment.IsDeleted != nil && *comment.IsDeleted {
+			header += " [deleted]"
+		}
+
+		var reactions []string
+		if comment.Reactions != nil {
+			for _, reaction := range *comment.Reactions {
+				if reaction.Type != nil && reaction.Count != nil && *reaction.Count > 0 {
+					reactions = append(reactions, fmt.Sprintf("%s: %d", *reaction.Type, *reaction.Count))
+				}
+			}
+		}
+
+		entry := fmt.Sprintf("%s:\n%s", header, text)
+		if len(reactions) > 0 {
+			entry += fmt.Sprintf("\nReactions: %s", strings.Join(reactions, ", "))
+		}
+		results = append(results, entry+"\n---")
+	}
+
+	if comments.ContinuationToken != nil && *comments.ContinuationToken != "" {
+		results = append(results, fmt.Sprintf("\nMore comments available. continuation_token: %s", *comments.ContinuationToken))
+	}
+
+	if includeImages {
+		images, notes := embeddedImages(ctx, commentHTML...)
+		return textWithImages(strings.Join(results, "\n"), images, notes), nil
+	}
+
+	return mcp.NewToolResultText(strings.Join(results, "\n")), nil
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffOp is one step of a line diff: lines kept, deleted from a or inserted from b
+type diffOp struct {
+	Kind   byte // ' ', '-' or '+'
+	AStart int
+	AEnd   int
+	BStart int
+	BEnd   int
+}
+
+// splitLines splits text into lines, ignoring a trailing newline
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// Largest LCS table lineMatches builds, in cells (16 MB of int32)
+const maxLineMatchCells = 4 * 1024 * 1024
+
+// lineMatches returns, for each line of a, the index of the matching line in b
+// on a longest common subsequence, or -1 when the line is not kept. When the changed
+// region is too large to compare, only the common prefix and suffix are matched and
+// ok is false.
+func lineMatches(a, b []string) (matches []int, ok bool) {
+	matches = make([]int, len(a))
+	for i := range matches {
+		matches[i] = -1
+	}
+
+	// Common prefix and suffix need no table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		matches[prefix] = prefix
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		matches[len(a)-1-suffix] = len(b) - 1 - suffix
+		suffix++
+	}
+
+	middleA := a[prefix : len(a)-suffix]
+	middleB := b[prefix : len(b)-suffix]
+	n, m := len(middleA), len(middleB)
+	if n == 0 || m == 0 {
+		return matches, true
+	}
+	if n*m > maxLineMatchCells {
+		return matches, false
+	}
+
+	// lengths[i][j] is the LCS length of middleA[i:] and middleB[j:]
+	lengths := make([][]int32, n+1)
+	for i := range lengths {
+		lengths[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case middleA[i] == middleB[j]:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case middleA[i] == middleB[j]:
+			matches[prefix+i] = prefix + j
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return matches, true
+}
+
+// diffLines computes the line diff turning a into b. Very large changed regions are
+// shown as replaced in full.
+func diffLines(a, b []string) []diffOp {
+	matches, _ := lineMatches(a, b)
+
+	var ops []diffOp
+	add := func(kind byte, aStart, aEnd, bStart, bEnd int) {
+		if aStart == aEnd && bStart == bEnd {
+			return
+		}
+		if n := len(ops); n > 0 && ops[n-1].Kind == kind {
+			ops[n-1].AEnd, ops[n-1].BEnd = aEnd, bEnd
+			return
+		}
+		ops = append(ops, diffOp{Kind: kind, AStart: aStart, AEnd: aEnd, BStart: bStart, BEnd: bEnd})
+	}
+
+	i, j := 0, 0
+	for i < len(a) {
+		if matches[i] == -1 {
+			add('-', i, i+1, j, j)
+			i++
+			continue
+		}
+		add('+', i, i, j, matches[i])
+		j = matches[i]
+		add(' ', i, i+1, j, j+1)
+		i++
+		j++
+	}
+	add('+', i, i, j, len(b))
+	return ops
+}
+
+// unifiedDiff renders a unified diff with the given number of context lines,
+// returning "" when the texts are equal
+func unifiedDiff(fromName, toName string, a, b []string, context int) string {
+	ops := diffLines(a, b)
+	if len(ops) == 0 || (len(ops) == 1 && ops[0].Kind == ' ') {
+		return ""
+	}
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+
+	for start := 0; start < len(ops); {
+		// Find the next change and the run of changes close enough to share a hunk
+		for start < len(ops) && ops[start].Kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		end := start + 1
+		for end < len(ops) {
+			if ops[end].Kind != ' ' {
+				end++
+				continue
+			}
+			if end+1 < len(ops) && ops[end].AEnd-ops[end].AStart <= 2*context {
+				end++
+				continue
+			}
+			break
+		}
+
+		aStart, bStart := ops[start].AStart, ops[start].BStart
+		if start > 0 {
+			keep := min(context, ops[start-1].AEnd-ops[start-1].AStart)
+			aStart -= keep
+			bStart -= keep
+		}
+		aEnd, bEnd := ops[end-1].AEnd, ops[end-1].BEnd
+		if end < len(ops) {
+			keep := min(context, ops[end].AEnd-ops[end].AStart)
+			aEnd += keep
+			bEnd += keep
+		}
+
+		out.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(aStart, aEnd), hunkRange(bStart, bEnd)))
+		for i := aStart; i < ops[start].AStart; i++ {
+			out.WriteString(" " + a[i] + "\n")
+		}
+		for _, op := range ops[start:end] {
+			switch op.Kind {
+			case ' ':
+				for i := op.AStart; i < op.AEnd; i++ {
+					out.WriteString(" " + a[i] + "\n")
+				}
+			case '-':
+				for i := op.AStart; i < op.AEnd; i++ {
+					out.WriteString("-" + a[i] + "\n")
+				}
+			case '+':
+				for j := op.BStart; j < op.BEnd; j++ {
+					out.WriteString("+" + b[j] + "\n")
+				}
+			}
+		}
+		for i := ops[end-1].AEnd; i < aEnd; i++ {
+			out.WriteString(" " + a[i] + "\n")
+		}
+
+		start = end
+	}
+	return out.String()
+}
+
+// hunkRange formats a hunk's line range, which starts before the first line when empty
+func hunkRange(start, end int) string {
+	if start == end {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, end-start)
+}
+
+// mergeLines merges the changes made in local and remote since base. Overlapping changes
+// are kept between conflict markers and counted as conflicts.
+func mergeLines(base, local, remote []string, localName, remoteName string) ([]string, int, error) {
+	localMatches, localOK := lineMatches(base, local)
+	remoteMatches, remoteOK := lineMatches(base, remote)
+	if !localOK || !remoteOK {
+		return nil, 0, fmt.Errorf("the changes are too large to merge")
+	}
+
+	var merged []string
+	conflicts := 0
+	i, l, r := 0, 0, 0
+	for {
+		// Copy lines that are unchanged on both sides
+		if i < len(base) && localMatches[i] == l && remoteMatches[i] == r {
+			merged = append(merged, base[i])
+			i++
+			l++
+			r++
+			continue
+		}
+
+		// Find the next base line both sides kept
+		k := i
+		for k < len(base) && (localMatches[k] == -1 || remoteMatches[k] == -1) {
+			k++
+		}
+		localEnd, remoteEnd := len(local), len(remote)
+		if k < len(base) {
+			localEnd, remoteEnd = localMatches[k], remoteMatches[k]
+		}
+
+		baseChunk, localChunk, remoteChunk := base[i:k], local[l:localEnd], remote[r:remoteEnd]
+		switch {
+		case equalLines(localChunk, baseChunk):
+			merged = append(merged, remoteChunk...)
+		case equalLines(remoteChunk, baseChunk), equalLines(localChunk, remoteChunk):
+			merged = append(merged, localChunk...)
+		default:
+			conflicts++
+			merged = append(merged, "<<<<<<< "+localName)
+			merged = append(merged, localChunk...)
+			merged = append(merged, "=======")
+			merged = append(merged, remoteChunk...)
+			merged = append(merged, ">>>>>>> "+remoteName)
+		}
+
+		if k == len(base) {
+			return merged, conflicts, nil
+		}
+		i, l, r = k, localEnd, remoteEnd
+	}
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"one", []string{"one"}},
+		{"one\ntwo\n", []string{"one", "two"}},
+		{"one\r\ntwo", []string{"one", "two"}},
+		{"one\n\n", []string{"one", ""}},
+	}
+	for _, tt := range tests {
+		if got := splitLines(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitLines(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLineMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []int
+	}{
+		{"equal", "a b c", "a b c", []int{0, 1, 2}},
+		{"deleted line", "a b c", "a c", []int{0, -1, 1}},
+		{"inserted line", "a c", "a b c", []int{0, 2}},
+		{"replaced line", "a b c", "a x c", []int{0, -1, 2}},
+		{"moved line", "a b c d", "b c d a", []int{-1, 0, 1, 2}},
+		{"empty b", "a b", "", []int{-1, -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := lineMatches(strings.Fields(tt.a), strings.Fields(tt.b))
+			if !ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lineMatches = %v, %v, want %v, true", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineMatchesTooLarge(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 3000; i++ {
+		a = append(a, fmt.Sprintf("a%d", i))
+		b = append(b, fmt.Sprintf("b%d", i))
+	}
+	a = append([]string{"same"}, append(a, "end")...)
+	b = append([]string{"same"}, append(b, "end")...)
+
+	matches, ok := lineMatches(a, b)
+	if ok {
+		t.Fatal("lineMatches should report that the changed region is too large")
+	}
+	if matches[0] != 0 || matches[len(a)-1] != len(b)-1 || matches[1] != -1 {
+		t.Errorf("lineMatches should still match the common prefix and suffix, got %v...", matches[:2])
+	}
+
+	if _, _, err := mergeLines(a, b, a, "local", "remote"); err == nil {
+		t.Error("mergeLines should fail when the changes are too large to merge")
+	}
+}
+
+func TestMergeLines(t *testing.T) {
+	tests := []struct {
+		name                string
+		base, local, remote string
+		want                []string
+		conflicts           int
+	}{
+		{"no changes", "a b c", "a b c", "a b c", []string{"a", "b", "c"}, 0},
+		{"local change", "a b c", "a x c", "a b c", []string{"a", "x", "c"}, 0},
+		{"remote change", "a b c", "a b c", "a b y", []string{"a", "b", "y"}, 0},
+		{"separate changes", "a b c d e", "x b c d e", "a b c d y", []string{"x", "b", "c", "d", "y"}, 0},
+		{"same change", "a b c", "a x c", "a x c", []string{"a", "x", "c"}, 0},
+		{"insert on both ends", "a b c", "z a b c", "a b c w", []string{"z", "a", "b", "c", "w"}, 0},
+		{"overlapping changes", "a b c", "a x c", "a y c",
+			[]string{"a", "<<<<<<< local", "x", "=======", "y", ">>>>>>> remote", "c"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts, err := mergeLines(strings.Fields(tt.base), strings.Fields(tt.local), strings.Fields(tt.remote), "local", "remote")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || conflicts != tt.conflicts {
+				t.Errorf("mergeLines = %q, %d conflicts, want %q, %d", got, conflicts, tt.want, tt.conflicts)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{"equal", "a b c", "a b c", 3, ""},
+		{"replaced line", "a b c", "a x c", 1, "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"},
+		{"insert into empty", "", "a", 3, "--- old\n+++ new\n@@ -0,0 +1,1 @@\n+a\n"},
+		{"separate hunks", "a b c d e f g h", "x b c d e f g y", 1, "--- old\n+++ new\n@@ -1,2 +1,2 @@\n-a\n+x\n b\n@@ -7,2 +7,2 @@\n g\n-h\n+y\n"},
+		{"joined hunks", "a b c d", "x b c y", 1, "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+x\n b\n c\n-d\n+y\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unifiedDiff("old", "new", strings.Fields(tt.a), strings.Fields(tt.b), tt.context)
+			if got != tt.want {
+				t.Errorf("unifiedDiff =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,8 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/mark3labs/mcp-go v0.8.3
 	github.com/microsoft/azure-devops-go-api/azuredevops/v7 v7.1.0
+	github.com/yuin/goldmark v1.7.8
+	golang.org/x/net v0.34.0
 )
 
 require (
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+)
+
+// Longest field value shown in history before it is truncated
+const maxHistoryValueLength = 300
+
+// parseDateArg parses a date argument given as YYYY-MM-DD or RFC3339
+func parseDateArg(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD or RFC3339)", value)
+}
+
+// formatFieldValue renders a work item field value for display
+func formatFieldValue(value interface{}) string {
+	if value == nil {
+		return "(empty)"
+	}
+
+	// Identity fields come back as objects
+	if identity, ok := value.(map[string]interface{}); ok {
+		if name, ok := identity["displayName"].(string); ok {
+			return name
+		}
+	}
+
+	text := fmt.Sprintf("%v", value)
+	if runes := []rune(text); len(runes) > maxHistoryValueLength {
+		text = string(runes[:maxHistoryValueLength]) + "..."
+	}
+	return text
+}
+
+// getAllWorkItemUpdates pages through every update of a work item
+func getAllWorkItemUpdates(ctx context.Context, id int) ([]workitemtracking.WorkItemUpdate, error) {
+	var updates []workitemtracking.WorkItemUpdate
+	pageSize := 200
+	for skip := 0; ; skip += pageSize {
+		top := pageSize
+		skipCount := skip
+		page, err := workItemClient.GetUpdates(ctx, workitemtracking.GetUpdatesArgs{
+			Id:      &id,
+			Project: &config.Project,
+			Top:     &top,
+			Skip:    &skipCount,
+		})
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, *page...)
+		if len(*page) < pageSize {
+			break
+		}
+	}
+	return updates, nil
+}
+
+// updateTimestamp returns when an update was made
+func updateTimestamp(update workitemtracking.WorkItemUpdate) time.Time {
+	// The latest revision carries a far-future RevisedDate, so prefer the changed date
+	if update.Fields != nil {
+		if changed, ok := (*update.Fields)["System.ChangedDate"]; ok {
+			if s, ok := changed.NewValue.(string); ok {
+				if t, err := time.Parse(time.RFC3339, s); err == nil {
+					return t
+				}
+			}
+		}
+	}
+	if update.RevisedDate != nil {
+		return update.RevisedDate.Time
+	}
+	return time.Time{}
+}
+
+// Handler for getting work item history
+func handleGetWorkItemHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := int(request.Params.Arguments["id"].(float64))
+	fieldFilter, _ := request.Params.Arguments["fields"].(string)
+	sinceStr, _ := request.Params.Arguments["since"].(string)
+	untilStr, _ := request.Params.Arguments["until"].(string)
+
+	var since, until time.Time
+	var err error
+	if sinceStr != "" {
+		if since, err = parseDateArg(sinceStr); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if untilStr != "" {
+		if until, err = parseDateArg(untilStr); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		// A bare date includes the whole day; a timestamp includes changes made at that instant
+		if !strings.Contains(untilStr, "T") {
+			until = until.Add(24*time.Hour - time.Nanosecond)
+		}
+	}
+
+	var fieldFilters []string
+	for _, f := range strings.Split(fieldFilter, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fieldFilters = append(fieldFilters, strings.ToLower(f))
+		}
+	}
+	matchesFilter := func(field string) bool {
+		if len(fieldFilters) == 0 {
+			return true
+		}
+		for _, f := range fieldFilters {
+			if strings.Contains(strings.ToLower(field), f) {
+				return true
+			}
+		}
+		return false
+	}
+
+	updates, err := getAllWorkItemUpdates(ctx, id)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get work item history: %v", err)), nil
+	}
+
+	// Bookkeeping fields that change on every revision
+	skipFields := map[string]bool{
+		"System.Rev":                            true,
+		"System.AuthorizedDate":                 true,
+		"System.RevisedDate":                    true,
+		"System.ChangedDate":                    true,
+		"System.Watermark":                      true,
+		"System.PersonId":                       true,
+		"System.AuthorizedAs":                   true,
+		"System.ChangedBy":                      true,
+		"System.CommentCount":                   true,
+		"Microsoft.VSTS.Common.StateChangeDate": true,
+	}
+
+	var results []string
+	for _, update := range updates {
+		timestamp := updateTimestamp(update)
+		if !since.IsZero() && timestamp.Before(since) {
+			continue
+		}
+		if !until.IsZero() && timestamp.After(until) {
+			continue
+		}
+
+		var changes []string
+		if update.Fields != nil {
+			var fieldNames []string
+			for field := range *update.Fields {
+				fieldNames = append(fieldNames, field)
+			}
+			sort.Strings(fieldNames)
+
+			for _, field := range fieldNames {
+				if skipFields[field] || !matchesFilter(field) {
+					continue
+				}
+				change := (*update.Fields)[field]
+				if htmlFieldReference(field) != "" {
+					if oldValue, ok := change.OldValue.(string); ok {
+						change.OldValue = htmlToMarkdown(oldValue)
+					}
+					if newValue, ok := change.NewValue.(string); ok {
+						change.NewValue = htmlToMarkdown(newValue)
+					}
+				}
+				if field == "System.History" {
+					changes = append(changes, fmt.Sprintf("  Comment: %s", formatFieldValue(change.NewValue)))
+					continue
+				}
+				changes = append(changes, fmt.Sprintf("  %s: %s → %s",
+					field, formatFieldValue(change.OldValue), formatFieldValue(change.NewValue)))
+			}
+		}
+
+		if update.Relations != nil && matchesFilter("relations") {
+			describe := func(relation workitemtracking.WorkItemRelation) string {
+				rel, target := "", ""
+				if relation.Rel != nil {
+					rel = *relation.Rel
+				}
+				if relation.Url != nil {
+					target = *relation.Url
+				}
+				return fmt.Sprintf("%s %s", rel, target)
+			}
+			if update.Relations.Added != nil {
+				for _, relation := range *update.Relations.Added {
+					changes = append(changes, "  Relation added: "+describe(relation))
+				}
+			}
+			if update.Relations.Removed != nil {
+				for _, relation := range *update.Relations.Removed {
+					changes = append(changes, "  Relation removed: "+describe(relation))
+				}
+			}
+			if update.Relations.Updated != nil {
+				for _, relation := range *update.Relations.Updated {
+					changes = append(changes, "  Relation updated: "+describe(relation))
+				}
+			}
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		revisedBy := "Unknown"
+		if update.RevisedBy != nil && update.RevisedBy.DisplayName != nil {
+			revisedBy = *update.RevisedBy.DisplayName
+		}
+		rev := 0
+		if update.Rev != nil {
+			rev = *update.Rev
+		}
+
+		results = append(results, fmt.Sprintf("Revision %d by %s at %s:\n%s\n---",
+			rev,
+			revisedBy,
+			timestamp.Format(time.RFC3339),
+			strings.Join(changes, "\n")))
+	}
+
+	if len(results) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No matching changes found for work item #%d", id)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("History for work item #%d:\n\n%s", id, strings.Join(results, "\n"))), nil
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// identity is a user or group returned by the identities API
+type identity struct {
+	ID          string
+	DisplayName string
+	Email       string
+	IsGroup     bool
+}
+
+// AssignedTo returns the value Azure DevOps expects in identity fields
+func (i identity) AssignedTo() string {
+	if i.Email == "" {
+		return i.DisplayName
+	}
+	return fmt.Sprintf("%s <%s>", i.DisplayName, i.Email)
+}
+
+// How long identity search results are reused before searching again
+const identityCacheTTL = 10 * time.Minute
+
+// identityCacheEntry is a cached identity search result
+type identityCacheEntry struct {
+	Identities []identity
+	Expires    time.Time
+}
+
+// Cached identity search results, keyed by lower-cased query
+var (
+	identityCacheMutex sync.Mutex
+	identityCache      = make(map[string]identityCacheEntry)
+)
+
+// Matches @alice or @alice@example.com mentions in comment text
+var mentionPattern = regexp.MustCompile(`(^|[\s(])@([A-Za-z0-9._\-]+(?:@[A-Za-z0-9.\-]+\.[A-Za-z]+)?)`)
+
+// Matches values already in "Display Name <email>" form
+var assignedToPattern = regexp.MustCompile(`^.+<[^<>@\s]+@[^<>\s]+>$`)
+
+// identitiesAPIURL builds the identities endpoint, which lives on the vssps host
+func identitiesAPIURL(query string) string {
+	base := strings.Replace(config.OrganizationURL, "https://dev.azure.com/", "https://vssps.dev.azure.com/", 1)
+	queryParams := url.Values{}
+	queryParams.Add("searchFilter", "General")
+	queryParams.Add("filterValue", query)
+	queryParams.Add("queryMembership", "None")
+	queryParams.Add("api-version", "7.1")
+	return fmt.Sprintf("%s/_apis/identities?%s", base, queryParams.Encode())
+}
+
+// searchIdentities finds users and groups by partial name or email, caching results
+func searchIdentities(ctx context.Context, query string) ([]identity, error) {
+	key := strings.ToLower(strings.TrimSpace(query))
+
+	identityCacheMutex.Lock()
+	cached, ok := identityCache[key]
+	identityCacheMutex.Unlock()
+	if ok && time.Now().Before(cached.Expires) {
+		return cached.Identities, nil
+	}
+
+	var response struct {
+		Value []struct {
+			ID                  string `json:"id"`
+			ProviderDisplayName string `json:"providerDisplayName"`
+			CustomDisplayName   string `json:"customDisplayName"`
+			IsActive            bool   `json:"isActive"`
+			IsContainer         bool   `json:"isContainer"`
+			Properties          map[string]struct {
+				Value interface{} `json:"$value"`
+			} `json:"properties"`
+		} `json:"value"`
+	}
+	if err := doAzureDevOpsJSON(ctx, http.MethodGet, identitiesAPIURL(query), nil, &response); err != nil {
+		return nil, err
+	}
+
+	var identities []identity
+	for _, v := range response.Value {
+		if !v.IsActive {
+			continue
+		}
+		name := v.CustomDisplayName
+		if name == "" {
+			name = v.ProviderDisplayName
+		}
+		email, _ := v.Properties["Mail"].Value.(string)
+		if email == "" {
+			if account, _ := v.Properties["Account"].Value.(string); strings.Contains(account, "@") {
+				email = account
+			}
+		}
+		identities = append(identities, identity{
+			ID:          v.ID,
+			DisplayName: name,
+			Email:       email,
+			IsGroup:     v.IsContainer,
+		})
+	}
+
+	identityCacheMutex.Lock()
+	identityCache[key] = identityCacheEntry{Identities: identities, Expires: time.Now().Add(identityCacheTTL)}
+	identityCacheMutex.Unlock()
+
+	return identities, nil
+}
+
+// findIdentity resolves a name or email to exactly one identity
+func findIdentity(ctx context.Context, query string) (identity, error) {
+	identities, err := searchIdentities(ctx, query)
+	if err != nil {
+		return identity{}, fmt.Errorf("failed to search identities: %v", err)
+	}
+	if len(identities) == 0 {
+		return identity{}, fmt.Errorf("no user or group found matching %q", query)
+	}
+	if len(identities) == 1 {
+		return identities[0], nil
+	}
+
+	// Prefer an exact match on email or display name
+	for _, i := range identities {
+		if strings.EqualFold(i.Email, query) || strings.EqualFold(i.DisplayName, query) {
+			return i, nil
+		}
+	}
+
+	var candidates []string
+	for _, i := range identities {
+		candidates = append(candidates, i.AssignedTo())
+	}
+	return identity{}, fmt.Errorf("%q matches several identities, be more specific: %s", query, strings.Join(candidates, "; "))
+}
+
+// getAuthenticatedUserName returns the display name of the user the access token belongs to
+func getAuthenticatedUserName(ctx context.Context) (string, error) {
+	var connectionData struct {
+		AuthenticatedUser struct {
+			ProviderDisplayName string `json:"providerDisplayName"`
+		} `json:"authenticatedUser"`
+	}
+	if err := doAzureDevOpsJSON(ctx, http.MethodGet, config.OrganizationURL+"/_apis/connectionData", nil, &connectionData); err != nil {
+		return "", fmt.Errorf("failed to get the authenticated user: %v", err)
+	}
+	return connectionData.AuthenticatedUser.ProviderDisplayName, nil
+}
+
+// isAssignedToField reports whether a field name refers to System.AssignedTo
+func isAssignedToField(field string) bool {
+	return strings.EqualFold(field, "AssignedTo") ||
+		strings.EqualFold(field, "Assigned To") ||
+		strings.EqualFold(field, "System.AssignedTo")
+}
+
+// resolveAssignedTo turns a partial name or email into an AssignedTo field value
+func resolveAssignedTo(ctx context.Context, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || assignedToPattern.MatchString(value) {
+		return value, nil
+	}
+
+	i, err := findIdentity(ctx, value)
+	if err != nil {
+		return "", err
+	}
+	return i.AssignedTo(), nil
+}
+
+// convertMentions rewrites @name mentions into Azure DevOps mention markup.
+// Mentions that cannot be resolved to a single identity are left as plain text.
+func convertMentions(ctx context.Context, text, format string) string {
+	return mentionPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := mentionPattern.FindStringSubmatch(match)
+		prefix, name := parts[1], parts[2]
+
+		// Keep sentence punctuation out of the name
+		trimmed := strings.TrimRight(name, ".-")
+		suffix := name[len(trimmed):]
+
+		i, err := findIdentity(ctx, trimmed)
+		if err != nil {
+			return match
+		}
+
+		if format == "html" {
+			return fmt.Sprintf(`%s<a href="#" data-vss-mention="version:2.0,%s">@%s</a>%s`,
+				prefix, i.ID, html.EscapeString(i.DisplayName), suffix)
+		}
+		return fmt.Sprintf("%s@<%s>%s", prefix, i.ID, suffix)
+	})
+}
+
+// Handler for searching users and groups
+func handleSearchIdentities(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := request.Params.Arguments["query"].(string)
+
+	identities, err := searchIdentities(ctx, query)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to search identities: %v", err)), nil
+	}
+
+	if len(identities) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No users or groups found matching '%s'", query)), nil
+	}
+
+	var results []string
+	for _, i := range identities {
+		kind := "User"
+		if i.IsGroup {
+			kind = "Group"
+		}
+		results = append(results, fmt.Sprintf("%s: %s\nEmail: %s\nID: %s\nAssign as: %s\n---",
+			kind, i.DisplayName, i.Email, i.ID, i.AssignedTo()))
+	}
+
+	return mcp.NewToolResultText(strings.Join(results, "\n")), nil
+}
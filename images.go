@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Most embedded images returned when reading a work item
+const maxEmbeddedImages = 10
+
+// Fields an inline image can be embedded into
+var inlineImageTargets = map[string]string{
+	"description": "System.Description",
+	"repro_steps": "Microsoft.VSTS.TCM.ReproSteps",
+}
+
+// imageContentType works out the MIME type of an image from its name, falling back to sniffing
+func imageContentType(fileName string, head []byte) string {
+	contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName)))
+	if contentType == "" {
+		contentType = http.DetectContentType(head)
+	}
+	return strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+}
+
+// Handler for uploading an image and embedding it in a work item field or comment
+func handleAddInlineImage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := int(request.Params.Arguments["id"].(float64))
+	target := request.Params.Arguments["target"].(string)
+	text, _ := request.Params.Arguments["text"].(string)
+	altText, _ := request.Params.Arguments["alt_text"].(string)
+	position, _ := request.Params.Arguments["position"].(string)
+
+	field, isField := inlineImageTargets[target]
+	if !isField && target != "comment" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid target: %s", target)), nil
+	}
+
+	source, size, fileName, closeSource, err := openUploadSource(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer closeSource()
+
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(source, head)
+	if _, err := source.Seek(0, io.SeekStart); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read file: %v", err)), nil
+	}
+	contentType := imageContentType(fileName, head[:n])
+	if !strings.HasPrefix(contentType, "image/") {
+		return mcp.NewToolResultError(fmt.Sprintf("'%s' is not an image (%s)", fileName, contentType)), nil
+	}
+	if altText == "" {
+		altText = fileName
+	}
+
+	attachmentURL, err := uploadAttachment(ctx, request, fileName, source, size)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to upload image: %v", err)), nil
+	}
+
+	// Azure DevOps serves inline images from the attachment URL with the file name appended
+	imageURL := fmt.Sprintf("%s?fileName=%s", attachmentURL, url.QueryEscape(fileName))
+	imageHTML := fmt.Sprintf(`<p><img src="%s" alt="%s"></p>`, html.EscapeString(imageURL), html.EscapeString(altText))
+	if text != "" {
+		imageHTML = convertMentions(ctx, markdownToHTML(text), "html") + imageHTML
+	}
+
+	if !isField {
+		comment, err := postWorkItemComment(ctx, id, sanitizeHTML(imageHTML), "html")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to add comment: %v", err)), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Added comment %d with image '%s' to work item #%d", comment.Id, fileName, id)), nil
+	}
+
+	workItem, err := workItemClient.GetWorkItem(ctx, workitemtracking.GetWorkItemArgs{
+		Id:      &id,
+		Project: &config.Project,
+		Fields:  &[]string{field},
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get work item: %v", err)), nil
+	}
+	existing := ""
+	if workItem.Fields != nil {
+		existing, _ = (*workItem.Fields)[field].(string)
+	}
+
+	// Only the new fragment is sanitized; the existing field value is kept exactly as stored
+	fragment := sanitizeHTML(imageHTML)
+	value := existing + fragment
+	if position == "prepend" {
+		value = fragment + existing
+	}
+
+	_, err = workItemClient.UpdateWorkItem(ctx, workitemtracking.UpdateWorkItemArgs{
+		Id:      &id,
+		Project: &config.Project,
+		Document: &[]webapi.JsonPatchOperation{
+			{
+				Op:    &webapi.OperationValues.Add,
+				Path:  stringPtr("/fields/" + field),
+				Value: value,
+			},
+		},
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to update work item: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Embedded image '%s' in %s of work item #%d", fileName, field, id)), nil
+}
+
+// imageSources returns the src of every <img> in an HTML fragment
+func imageSources(fragment string) []string {
+	nodes, err := parseHTMLFragment(fragment)
+	if err != nil {
+		return nil
+	}
+
+	var sources []string
+	var walk func(n *xhtml.Node)
+	walk = func(n *xhtml.Node) {
+		if n.Type == xhtml.ElementNode && n.DataAtom == atom.Img {
+			if src := attrValue(n, "src"); src != "" {
+				sources = append(sources, src)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for _, node := range nodes {
+		walk(node)
+	}
+	return sources
+}
+
+// fetchEmbeddedImage downloads an image referenced from work item HTML
+func fetchEmbeddedImage(ctx context.Context, src string) (mcp.ImageContent, error) {
+	// Images pasted into the web UI are sometimes stored as data URIs
+	if strings.HasPrefix(src, "data:image/") {
+		header, data, found := strings.Cut(strings.TrimPrefix(src, "data:"), ",")
+		if !found || !strings.HasSuffix(header, ";base64") {
+			return mcp.ImageContent{}, fmt.Errorf("unsupported data URI")
+		}
+		contentType := strings.TrimSuffix(header, ";base64")
+		if !inlineImageTypes[contentType] {
+			return mcp.ImageContent{}, fmt.Errorf("unsupported image type %s", contentType)
+		}
+		return mcp.ImageContent{Type: "image", Data: data, MIMEType: contentType}, nil
+	}
+
+	if !strings.Contains(strings.ToLower(src), "/_apis/wit/attachments/") {
+		return mcp.ImageContent{}, fmt.Errorf("not a work item attachment")
+	}
+	attachmentID, ok := attachmentIDFromURL(src)
+	if !ok {
+		return mcp.ImageContent{}, fmt.Errorf("invalid attachment URL")
+	}
+	fileName := attachmentID.String()
+	if parsed, err := url.Parse(src); err == nil && parsed.Query().Get("fileName") != "" {
+		fileName = parsed.Query().Get("fileName")
+	}
+
+	stream, err := workItemClient.GetAttachmentContent(ctx, workitemtracking.GetAttachmentContentArgs{
+		Id:       &attachmentID,
+		Project:  &config.Project,
+		FileName: &fileName,
+	})
+	if err != nil {
+		return mcp.ImageContent{}, err
+	}
+	defer stream.Close()
+
+	reader := bufio.NewReader(stream)
+	head, _ := reader.Peek(512)
+	contentType := imageContentType(fileName, head)
+	if !inlineImageTypes[contentType] {
+		return mcp.ImageContent{}, fmt.Errorf("unsupported image type %s", contentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxInlineBinarySize+1))
+	if err != nil {
+		return mcp.ImageContent{}, err
+	}
+	if len(data) > maxInlineBinarySize {
+		return mcp.ImageContent{}, fmt.Errorf("larger than %d bytes", maxInlineBinarySize)
+	}
+	return mcp.ImageContent{
+		Type:     "image",
+		Data:     base64.StdEncoding.EncodeToString(data),
+		MIMEType: contentType,
+	}, nil
+}
+
+// embeddedImages collects the images embedded in HTML fragments as MCP image content,
+// along with notes about images that could not be returned
+func embeddedImages(ctx context.Context, fragments ...string) ([]interface{}, []string) {
+	var images []interface{}
+	var notes []string
+	seen := make(map[string]bool)
+	for _, fragment := range fragments {
+		for _, src := range imageSources(fragment) {
+			if seen[src] {
+				continue
+			}
+			seen[src] = true
+			if len(images) == maxEmbeddedImages {
+				notes = append(notes, fmt.Sprintf("Skipped %s: only the first %d images are returned", src, maxEmbeddedImages))
+				continue
+			}
+			image, err := fetchEmbeddedImage(ctx, src)
+			if err != nil {
+				notes = append(notes, fmt.Sprintf("Skipped image %s: %v", src, err))
+				continue
+			}
+			images = append(images, image)
+		}
+	}
+	return images, notes
+}
+
+// textWithImages builds a tool result holding text followed by image content
+func textWithImages(text string, images []interface{}, notes []string) *mcp.CallToolResult {
+	if len(notes) > 0 {
+		text += "\n" + strings.Join(notes, "\n")
+	}
+	content := []interface{}{mcp.TextContent{Type: "text", Text: text}}
+	return &mcp.CallToolResult{Content: append(content, images...)}
+}
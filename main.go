@@ -1,15 +1,23 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/core"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
 )
@@ -19,6 +27,13 @@ type AzureDevOpsConfig struct {
 	OrganizationURL     string
 	PersonalAccessToken string
 	Project             string
+	DownloadDirectory   string // Optional local directory where attachments are saved
+	UploadDirectory     string // Optional local directory that files may be uploaded from
+	DefaultWiki         string // Optional name or ID of the wiki used when a tool call names none
+	IndexDirectory      string // Optional local directory where wiki search indexes are stored
+	WikiTemplatePath    string // Wiki folder whose sub-pages are page templates
+	WikiTemplateDir     string // Optional local directory of markdown page templates
+	DefaultTeam         string // Optional team used by sprint tools when a tool call names none
 }
 
 // Global clients and config
@@ -27,9 +42,16 @@ var (
 	workItemClient workitemtracking.Client
 	wikiClient     wiki.Client
 	coreClient     core.Client
+	gitClient      git.Client
 	config         AzureDevOpsConfig
 )
 
+// Cached project ID, needed to build artifact link URIs
+var (
+	projectIDMutex sync.Mutex
+	projectID      string
+)
+
 func main() {
 	// Main function for the MCP server - handles initialization and startup
 	// Load configuration from environment variables
@@ -37,6 +59,16 @@ func main() {
 		OrganizationURL:     "https://dev.azure.com/" + os.Getenv("AZURE_DEVOPS_ORG"),
 		PersonalAccessToken: os.Getenv("AZDO_PAT"),
 		Project:             os.Getenv("AZURE_DEVOPS_PROJECT"),
+		DownloadDirectory:   os.Getenv("AZURE_DEVOPS_DOWNLOAD_DIR"),
+		UploadDirectory:     os.Getenv("AZURE_DEVOPS_UPLOAD_DIR"),
+		DefaultWiki:         os.Getenv("AZURE_DEVOPS_WIKI"),
+		IndexDirectory:      os.Getenv("AZURE_DEVOPS_INDEX_DIR"),
+		WikiTemplatePath:    os.Getenv("AZURE_DEVOPS_WIKI_TEMPLATES"),
+		WikiTemplateDir:     os.Getenv("AZURE_DEVOPS_WIKI_TEMPLATE_DIR"),
+		DefaultTeam:         os.Getenv("AZURE_DEVOPS_TEAM"),
+	}
+	if config.WikiTemplatePath == "" {
+		config.WikiTemplatePath = "/Templates"
 	}
 
 	// Validate configuration
@@ -88,10 +120,93 @@ func min(a, b int) int {
 	return b
 }
 
+// azureStatusCode returns the HTTP status code carried by an Azure DevOps SDK error, or 0
+func azureStatusCode(err error) int {
+	var wrapped *azuredevops.WrappedError
+	if errors.As(err, &wrapped) && wrapped.StatusCode != nil {
+		return *wrapped.StatusCode
+	}
+	var value azuredevops.WrappedError
+	if errors.As(err, &value) && value.StatusCode != nil {
+		return *value.StatusCode
+	}
+	return 0
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
 
+// sendAzureDevOpsRequest sends an authenticated request to the Azure DevOps REST API
+func sendAzureDevOpsRequest(ctx context.Context, method, fullURL string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.SetBasicAuth("", config.PersonalAccessToken)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	client := &http.Client{}
+	return client.Do(req)
+}
+
+// doAzureDevOpsJSON sends a JSON request and decodes the JSON response into result (if not nil)
+func doAzureDevOpsJSON(ctx context.Context, method, fullURL string, payload interface{}, result interface{}) error {
+	var body io.Reader
+	headers := map[string]string{}
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %v", err)
+		}
+		body = bytes.NewReader(payloadBytes)
+		headers["Content-Type"] = "application/json"
+	}
+
+	resp, err := sendAzureDevOpsRequest(ctx, method, fullURL, body, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		log.Printf("Azure DevOps API Error - Status: %d, Response: %s", resp.StatusCode, string(responseBody))
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, apiErrorMessage(responseBody))
+	}
+
+	if result != nil && len(responseBody) > 0 {
+		if err := json.Unmarshal(responseBody, result); err != nil {
+			return fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+	return nil
+}
+
+// apiErrorMessage extracts the message from an Azure DevOps error response
+func apiErrorMessage(body []byte) string {
+	var apiError struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &apiError); err == nil && apiError.Message != "" {
+		return apiError.Message
+	}
+	return strings.TrimSpace(string(body[:min(len(body), 200)]))
+}
+
+// projectAPIURL builds a project-scoped REST API URL
+func projectAPIURL(format string, args ...interface{}) string {
+	return fmt.Sprintf("%s/%s/_apis/", config.OrganizationURL, url.PathEscape(config.Project)) +
+		fmt.Sprintf(format, args...)
+}
+
 // Initialize Azure DevOps clients
 func initializeClients(config AzureDevOpsConfig) error {
 	connection = azuredevops.NewPatConnection(config.OrganizationURL, config.PersonalAccessToken)
@@ -118,9 +233,38 @@ func initializeClients(config AzureDevOpsConfig) error {
 		return fmt.Errorf("failed to create core client: %v", err)
 	}
 
+	// Initialize Git client
+	gitClient, err = git.NewClient(ctx, connection)
+	if err != nil {
+		return fmt.Errorf("failed to create git client: %v", err)
+	}
+
 	return nil
 }
 
+// getProjectID returns the GUID of the configured project, looking it up once
+func getProjectID(ctx context.Context) (string, error) {
+	projectIDMutex.Lock()
+	defer projectIDMutex.Unlock()
+
+	if projectID != "" {
+		return projectID, nil
+	}
+
+	project, err := coreClient.GetProject(ctx, core.GetProjectArgs{
+		ProjectId: &config.Project,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get project: %v", err)
+	}
+	if project.Id == nil {
+		return "", fmt.Errorf("project %s has no ID", config.Project)
+	}
+
+	projectID = project.Id.String()
+	return projectID, nil
+}
+
 type logWriter struct{}
 
 func (w *logWriter) Write(bytes []byte) (int, error) {
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Markdown renderer with GitHub flavored extensions (tables, task lists, strikethrough, autolinks).
+// Raw HTML in the markdown source is kept and cleaned up by sanitizeHTML afterwards.
+var markdownRenderer = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+)
+
+// Work item fields that hold HTML
+var htmlFields = map[string]string{
+	"description":        "System.Description",
+	"reprosteps":         "Microsoft.VSTS.TCM.ReproSteps",
+	"acceptancecriteria": "Microsoft.VSTS.Common.AcceptanceCriteria",
+	"systeminfo":         "Microsoft.VSTS.TCM.SystemInfo",
+	"history":            "System.History",
+}
+
+// Elements kept by sanitizeHTML; anything else is unwrapped
+var allowedHTMLElements = map[atom.Atom]bool{
+	atom.P: true, atom.Br: true, atom.Div: true, atom.Span: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Strong: true, atom.B: true, atom.Em: true, atom.I: true, atom.U: true,
+	atom.S: true, atom.Del: true, atom.Strike: true, atom.Sup: true, atom.Sub: true,
+	atom.Code: true, atom.Pre: true, atom.Blockquote: true, atom.Hr: true,
+	atom.A: true, atom.Img: true, atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Input: true,
+	atom.Table: true, atom.Thead: true, atom.Tbody: true, atom.Tr: true, atom.Th: true, atom.Td: true,
+}
+
+// Elements removed together with their content
+var droppedHTMLElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Object: true,
+	atom.Embed: true, atom.Form: true, atom.Noscript: true, atom.Head: true, atom.Title: true,
+}
+
+// Attributes kept by sanitizeHTML, per element ("*" applies to all elements)
+var allowedHTMLAttributes = map[string]map[string]bool{
+	"*":     {"title": true},
+	"a":     {"href": true, "data-vss-mention": true},
+	"img":   {"src": true, "alt": true, "width": true, "height": true},
+	"td":    {"colspan": true, "rowspan": true, "align": true},
+	"th":    {"colspan": true, "rowspan": true, "align": true},
+	"ol":    {"start": true},
+	"code":  {"class": true},
+	"input": {"type": true, "checked": true, "disabled": true},
+}
+
+// Matches text that is already HTML rather than markdown
+var htmlStartPattern = regexp.MustCompile(`(?is)^\s*<(p|div|span|ul|ol|table|h[1-6]|br|b|i|u|strong|em|pre|code|img|a|blockquote)\b[^>]*>`)
+
+var (
+	blankLinesPattern = regexp.MustCompile(`\n{3,}`)
+	whitespacePattern = regexp.MustCompile(`[ \t\r\n]+`)
+)
+
+// htmlFieldReference returns the reference name of an HTML field, or "" if the field is not HTML
+func htmlFieldReference(field string) string {
+	for alias, reference := range htmlFields {
+		normalized := strings.ToLower(strings.NewReplacer(" ", "", ".", "").Replace(field))
+		if normalized == alias || strings.EqualFold(field, reference) {
+			return reference
+		}
+	}
+	return ""
+}
+
+// looksLikeHTML reports whether text already is HTML
+func looksLikeHTML(text string) bool {
+	return htmlStartPattern.MatchString(text)
+}
+
+// markdownToHTML renders markdown as sanitized Azure DevOps HTML. Text that is already HTML is only sanitized.
+func markdownToHTML(markdown string) string {
+	if looksLikeHTML(markdown) {
+		return sanitizeHTML(markdown)
+	}
+
+	var buf bytes.Buffer
+	if err := markdownRenderer.Convert([]byte(markdown), &buf); err != nil {
+		return sanitizeHTML("<p>" + html.EscapeString(markdown) + "</p>")
+	}
+	return sanitizeHTML(buf.String())
+}
+
+// convertFieldValue converts a markdown value for an HTML field, leaving other fields untouched
+func convertFieldValue(field string, value interface{}, format string) interface{} {
+	text, ok := value.(string)
+	if !ok || htmlFieldReference(field) == "" {
+		return value
+	}
+	if format == "html" {
+		return sanitizeHTML(text)
+	}
+	return markdownToHTML(text)
+}
+
+// parseHTMLFragment parses an HTML fragment in a <body> context
+func parseHTMLFragment(fragment string) ([]*html.Node, error) {
+	return html.ParseFragment(strings.NewReader(fragment), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+}
+
+// sanitizeHTML keeps a safe subset of elements and attributes
+func sanitizeHTML(fragment string) string {
+	nodes, err := parseHTMLFragment(fragment)
+	if err != nil {
+		return html.EscapeString(fragment)
+	}
+
+	var buf bytes.Buffer
+	for _, node := range nodes {
+		for _, clean := range sanitizeNode(node) {
+			_ = html.Render(&buf, clean)
+		}
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// sanitizeNode returns the sanitized replacement nodes for n
+func sanitizeNode(n *html.Node) []*html.Node {
+	switch n.Type {
+	case html.TextNode:
+		return []*html.Node{{Type: html.TextNode, Data: n.Data}}
+	case html.ElementNode:
+	default:
+		return nil
+	}
+
+	if droppedHTMLElements[n.DataAtom] {
+		return nil
+	}
+
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, sanitizeNode(c)...)
+	}
+
+	if !allowedHTMLElements[n.DataAtom] {
+		return children
+	}
+
+	clean := &html.Node{Type: html.ElementNode, Data: n.Data, DataAtom: n.DataAtom}
+	for _, attr := range n.Attr {
+		key := strings.ToLower(attr.Key)
+		if !allowedHTMLAttributes["*"][key] && !allowedHTMLAttributes[n.Data][key] {
+			continue
+		}
+		if (key == "href" || key == "src") && !isSafeURL(attr.Val, n.DataAtom == atom.Img) {
+			continue
+		}
+		if n.DataAtom == atom.Input && key == "type" && attr.Val != "checkbox" {
+			return children
+		}
+		clean.Attr = append(clean.Attr, html.Attribute{Key: key, Val: attr.Val})
+	}
+	for _, child := range children {
+		clean.AppendChild(child)
+	}
+	return []*html.Node{clean}
+}
+
+// isSafeURL rejects javascript: and other active URL schemes
+func isSafeURL(u string, isImage bool) bool {
+	lower := strings.ToLower(strings.TrimSpace(u))
+	if isImage && strings.HasPrefix(lower, "data:image/") {
+		return true
+	}
+	if i := strings.Index(lower, ":"); i != -1 && !strings.ContainsAny(lower[:i], "/?#") {
+		scheme := lower[:i]
+		return scheme == "http" || scheme == "https" || scheme == "mailto"
+	}
+	return true
+}
+
+// htmlToMarkdown converts stored Azure DevOps HTML back into readable markdown
+func htmlToMarkdown(fragment string) string {
+	if strings.TrimSpace(fragment) == "" {
+		return ""
+	}
+
+	nodes, err := parseHTMLFragment(fragment)
+	if err != nil {
+		return fragment
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		b.WriteString(nodeToMarkdown(node, 0))
+	}
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	result := blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(result)
+}
+
+// childrenToMarkdown converts all children of n
+func childrenToMarkdown(n *html.Node, depth int) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(nodeToMarkdown(c, depth))
+	}
+	return b.String()
+}
+
+// nodeToMarkdown converts a single node; depth is the current list nesting level
+func nodeToMarkdown(n *html.Node, depth int) string {
+	if n.Type == html.TextNode {
+		return whitespacePattern.ReplaceAllString(n.Data, " ")
+	}
+	if n.Type != html.ElementNode {
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Head, atom.Title:
+		return ""
+	case atom.Br:
+		return "\n"
+	case atom.Hr:
+		return "\n\n---\n\n"
+	case atom.P:
+		return "\n\n" + strings.TrimSpace(childrenToMarkdown(n, depth)) + "\n\n"
+	case atom.Div:
+		return "\n" + strings.TrimSpace(childrenToMarkdown(n, depth)) + "\n"
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		return "\n\n" + strings.Repeat("#", level) + " " + strings.TrimSpace(childrenToMarkdown(n, depth)) + "\n\n"
+	case atom.Strong, atom.B:
+		return wrapInline(childrenToMarkdown(n, depth), "**")
+	case atom.Em, atom.I:
+		return wrapInline(childrenToMarkdown(n, depth), "_")
+	case atom.S, atom.Del, atom.Strike:
+		return wrapInline(childrenToMarkdown(n, depth), "~~")
+	case atom.Code:
+		return wrapInline(textContent(n), "`")
+	case atom.Pre:
+		language := ""
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom == atom.Code {
+				language = strings.TrimPrefix(attrValue(c, "class"), "language-")
+			}
+		}
+		return "\n\n```" + language + "\n" + strings.Trim(textContent(n), "\n") + "\n```\n\n"
+	case atom.A:
+		text := strings.TrimSpace(childrenToMarkdown(n, depth))
+		href := attrValue(n, "href")
+		if attrValue(n, "data-vss-mention") != "" || href == "" || href == "#" {
+			return text
+		}
+		if text == "" || text == href {
+			return "<" + href + ">"
+		}
+		return fmt.Sprintf("[%s](%s)", text, href)
+	case atom.Img:
+		return fmt.Sprintf("![%s](%s)", attrValue(n, "alt"), attrValue(n, "src"))
+	case atom.Input:
+		if attrValue(n, "type") == "checkbox" {
+			if hasAttr(n, "checked") {
+				return "[x] "
+			}
+			return "[ ] "
+		}
+		return ""
+	case atom.Ul, atom.Ol:
+		return listToMarkdown(n, depth)
+	case atom.Blockquote:
+		content := strings.TrimSpace(childrenToMarkdown(n, depth))
+		var lines []string
+		for _, line := range strings.Split(content, "\n") {
+			lines = append(lines, strings.TrimRight("> "+line, " "))
+		}
+		return "\n\n" + strings.Join(lines, "\n") + "\n\n"
+	case atom.Table:
+		return tableToMarkdown(n)
+	}
+
+	return childrenToMarkdown(n, depth)
+}
+
+// listToMarkdown converts a <ul> or <ol> including nested lists
+func listToMarkdown(n *html.Node, depth int) string {
+	var b strings.Builder
+	indent := strings.Repeat("  ", depth)
+	number := 1
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+
+		content := strings.TrimSpace(blankLinesPattern.ReplaceAllString(childrenToMarkdown(c, depth+1), "\n"))
+		content = strings.ReplaceAll(content, "\n\n", "\n")
+		lines := strings.Split(content, "\n")
+		first := strings.Join(strings.Fields(lines[0]), " ")
+		b.WriteString(indent + marker + first + "\n")
+		for _, line := range lines[1:] {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			// Nested list items already carry their own indentation
+			if strings.HasPrefix(line, indent+"  ") {
+				b.WriteString(line + "\n")
+			} else {
+				b.WriteString(indent + "  " + strings.TrimSpace(line) + "\n")
+			}
+		}
+	}
+
+	if depth > 0 {
+		return "\n" + b.String()
+	}
+	return "\n\n" + b.String() + "\n"
+}
+
+// tableToMarkdown converts a table into a pipe table, using the first row as the header
+func tableToMarkdown(table *html.Node) string {
+	var rows [][]string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom == atom.Tr {
+				var cells []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+						text := strings.TrimSpace(childrenToMarkdown(cell, 0))
+						text = strings.ReplaceAll(whitespacePattern.ReplaceAllString(text, " "), "|", `\|`)
+						cells = append(cells, text)
+					}
+				}
+				rows = append(rows, cells)
+			} else {
+				walk(c)
+			}
+		}
+	}
+	walk(table)
+
+	if len(rows) == 0 {
+		return ""
+	}
+
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n")
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// wrapInline wraps inline text in a markdown delimiter, keeping surrounding spaces outside
+func wrapInline(text, delimiter string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	leading := text[:len(text)-len(strings.TrimLeft(text, " "))]
+	trailing := text[len(strings.TrimRight(text, " ")):]
+	return leading + delimiter + trimmed + delimiter + trailing
+}
+
+// textContent returns the raw text below a node
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	if n.DataAtom == atom.Br {
+		return "\n"
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+func attrValue(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
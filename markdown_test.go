@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"keeps allowed elements", `<p>Hello <strong>world</strong></p>`, `<p>Hello <strong>world</strong></p>`},
+		{"drops scripts", `<p>Hi</p><script>alert(1)</script>`, `<p>Hi</p>`},
+		{"unwraps unknown elements", `<custom>text</custom>`, `text`},
+		{"drops event handlers", `<p onclick="alert(1)">Hi</p>`, `<p>Hi</p>`},
+		{"drops javascript links", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"keeps https links", `<a href="https://example.com">x</a>`, `<a href="https://example.com">x</a>`},
+		{"keeps data image sources", `<img src="data:image/png;base64,AAAA">`, `<img src="data:image/png;base64,AAAA"/>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeHTML(tt.input); got != tt.want {
+				t.Errorf("sanitizeHTML(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownToHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"renders emphasis", "Some **bold** text", "<p>Some <strong>bold</strong> text</p>"},
+		{"keeps safe raw HTML", "Line<br>break", "<p>Line<br/>break</p>"},
+		{"sanitizes raw HTML", "Hi <span onclick=\"x()\">there</span>", "<p>Hi <span>there</span></p>"},
+		{"drops raw scripts", "<script>alert(1)</script>\n\nText", "<p>Text</p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownToHTML(tt.input); got != tt.want {
+				t.Errorf("markdownToHTML(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLToMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "  ", ""},
+		{"paragraphs", "<p>One</p><p>Two</p>", "One\n\nTwo"},
+		{"inline formatting", "<p><b>bold</b> and <i>italic</i> and <code>x</code></p>", "**bold** and _italic_ and `x`"},
+		{"headings", "<h2>Title</h2><p>Body</p>", "## Title\n\nBody"},
+		{"links", `<a href="https://example.com">site</a>`, "[site](https://example.com)"},
+		{"bare links", `<a href="https://example.com">https://example.com</a>`, "<https://example.com>"},
+		{"mentions", `<a href="#" data-vss-mention="version:2.0,1">@Alice</a>`, "@Alice"},
+		{"images", `<img src="https://example.com/a.png" alt="diagram">`, "![diagram](https://example.com/a.png)"},
+		{"code blocks", `<pre><code class="language-go">x := 1</code></pre>`, "```go\nx := 1\n```"},
+		{"line breaks", "one<br>two", "one\ntwo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := htmlToMarkdown(tt.input); got != tt.want {
+				t.Errorf("htmlToMarkdown(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+)
+
+// Friendly relation names mapped to Azure DevOps reference names
+var relationAliases = map[string]string{
+	"parent":      "System.LinkTypes.Hierarchy-Reverse",
+	"child":       "System.LinkTypes.Hierarchy-Forward",
+	"children":    "System.LinkTypes.Hierarchy-Forward",
+	"related":     "System.LinkTypes.Related",
+	"predecessor": "System.LinkTypes.Dependency-Reverse",
+	"successor":   "System.LinkTypes.Dependency-Forward",
+	"duplicate":   "System.LinkTypes.Duplicate-Forward",
+	"duplicateof": "System.LinkTypes.Duplicate-Reverse",
+	"testedby":    "Microsoft.VSTS.Common.TestedBy-Forward",
+	"tests":       "Microsoft.VSTS.Common.TestedBy-Reverse",
+	"affects":     "Microsoft.VSTS.Common.Affects-Forward",
+	"affectedby":  "Microsoft.VSTS.Common.Affects-Reverse",
+	"hyperlink":   "Hyperlink",
+	"artifact":    "ArtifactLink",
+}
+
+// Artifact link types, mapped to the link name Azure DevOps shows in the UI
+var artifactLinkNames = map[string]string{
+	"commit":       "Fixed in Commit",
+	"pull_request": "Pull Request",
+	"build":        "Build",
+	"wiki_page":    "Wiki Page",
+}
+
+// Cached relation type catalog
+var (
+	relationTypesMutex sync.Mutex
+	relationTypes      []workitemtracking.WorkItemRelationType
+)
+
+// getRelationTypes returns the relation type catalog, fetching it once
+func getRelationTypes(ctx context.Context) ([]workitemtracking.WorkItemRelationType, error) {
+	relationTypesMutex.Lock()
+	defer relationTypesMutex.Unlock()
+
+	if relationTypes != nil {
+		return relationTypes, nil
+	}
+
+	types, err := workItemClient.GetRelationTypes(ctx, workitemtracking.GetRelationTypesArgs{})
+	if err != nil {
+		return nil, err
+	}
+	relationTypes = *types
+	return relationTypes, nil
+}
+
+// resolveRelationType maps a friendly name, display name or reference name to a reference name
+func resolveRelationType(ctx context.Context, relationType string) (string, error) {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", "_", "", " ", "").Replace(relationType))
+	if referenceName, ok := relationAliases[normalized]; ok {
+		return referenceName, nil
+	}
+
+	types, err := getRelationTypes(ctx)
+	if err != nil {
+		// Without the catalog we can still accept anything that looks like a reference name
+		if strings.Contains(relationType, ".") {
+			return relationType, nil
+		}
+		return "", fmt.Errorf("failed to get relation types: %v", err)
+	}
+
+	for _, t := range types {
+		if t.ReferenceName == nil {
+			continue
+		}
+		if strings.EqualFold(*t.ReferenceName, relationType) ||
+			(t.Name != nil && strings.EqualFold(*t.Name, relationType)) {
+			return *t.ReferenceName, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown relation type: %s (use list_link_types to see available types)", relationType)
+}
+
+// buildArtifactURI builds the vstfs:/// URI for a commit, pull request, build or wiki page
+func buildArtifactURI(ctx context.Context, artifactType, artifactID, repository, wikiName string) (string, error) {
+	if artifactID == "" {
+		return "", fmt.Errorf("artifact_id is required for artifact links")
+	}
+
+	switch artifactType {
+	case "build":
+		if _, err := strconv.Atoi(artifactID); err != nil {
+			return "", fmt.Errorf("invalid build ID: %s", artifactID)
+		}
+		return "vstfs:///Build/Build/" + artifactID, nil
+
+	case "commit", "pull_request":
+		if repository == "" {
+			return "", fmt.Errorf("repository is required for %s links", artifactType)
+		}
+		projectID, err := getProjectID(ctx)
+		if err != nil {
+			return "", err
+		}
+		repo, err := gitClient.GetRepository(ctx, git.GetRepositoryArgs{
+			RepositoryId: &repository,
+			Project:      &config.Project,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to get repository %s: %v", repository, err)
+		}
+		kind := "Commit"
+		if artifactType == "pull_request" {
+			kind = "PullRequestId"
+		}
+		return fmt.Sprintf("vstfs:///Git/%s/%s%%2F%s%%2F%s", kind, projectID, repo.Id.String(), artifactID), nil
+
+	case "wiki_page":
+		projectID, err := getProjectID(ctx)
+		if err != nil {
+			return "", err
+		}
+		selectedWiki, err := resolveWiki(ctx, wikiName)
+		if err != nil {
+			return "", err
+		}
+		wikiID := selectedWiki.Id.String()
+		pagePath := artifactID
+		if !strings.HasPrefix(pagePath, "/") {
+			pagePath = "/" + pagePath
+		}
+		return fmt.Sprintf("vstfs:///Wiki/WikiPage/%s%%2F%s%%2F%s", projectID, wikiID, url.PathEscape(pagePath)), nil
+	}
+
+	return "", fmt.Errorf("unsupported artifact_type: %s", artifactType)
+}
+
+// relationTargetMatches reports whether a relation URL points at the given target
+func relationTargetMatches(relationURL, targetURL string) bool {
+	// Work item URLs differ in casing and host form, so compare by ID
+	if idx := strings.LastIndex(strings.ToLower(targetURL), "/workitems/"); idx != -1 {
+		relIdx := strings.LastIndex(strings.ToLower(relationURL), "/workitems/")
+		return relIdx != -1 && strings.EqualFold(relationURL[relIdx:], targetURL[idx:])
+	}
+
+	normalize := func(u string) string {
+		if unescaped, err := url.PathUnescape(u); err == nil {
+			u = unescaped
+		}
+		return strings.TrimSuffix(strings.ToLower(u), "/")
+	}
+	return normalize(relationURL) == normalize(targetURL)
+}
+
+// relatedWorkItemID returns the ID of the work item a relation points at. Hyperlinks,
+// artifact links and attachments are not work items.
+func relatedWorkItemID(relationURL string) (int, bool) {
+	idx := strings.LastIndex(strings.ToLower(relationURL), "/_apis/wit/workitems/")
+	if idx == -1 {
+		return 0, false
+	}
+	id, err := strconv.Atoi(relationURL[idx+len("/_apis/wit/workitems/"):])
+	return id, err == nil
+}
+
+// Handler for listing available link types
+func handleListLinkTypes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	types, err := getRelationTypes(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get relation types: %v", err)), nil
+	}
+
+	var workItemLinks, resourceLinks []string
+	for _, t := range types {
+		if t.Name == nil || t.ReferenceName == nil {
+			continue
+		}
+		var attributes map[string]interface{}
+		if t.Attributes != nil {
+			attributes = *t.Attributes
+		}
+		if enabled, ok := attributes["enabled"].(bool); ok && !enabled {
+			continue
+		}
+
+		line := fmt.Sprintf("- %s (%s)", *t.Name, *t.ReferenceName)
+		if topology, ok := attributes["topology"].(string); ok {
+			line += fmt.Sprintf(" [%s]", topology)
+		}
+
+		if usage, _ := attributes["usage"].(string); usage == "resourceLink" {
+			resourceLinks = append(resourceLinks, line)
+		} else {
+			workItemLinks = append(workItemLinks, line)
+		}
+	}
+	sort.Strings(workItemLinks)
+	sort.Strings(resourceLinks)
+
+	var result strings.Builder
+	result.WriteString("Work item link types:\n")
+	result.WriteString(strings.Join(workItemLinks, "\n"))
+	result.WriteString("\n\nResource link types:\n")
+	result.WriteString(strings.Join(resourceLinks, "\n"))
+
+	return mcp.NewToolResultText(result.String()), nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+)
+
+// Fields compared by compare_snapshots when none are given
+var defaultSnapshotFields = []string{
+	"System.Title",
+	"System.State",
+	"System.AssignedTo",
+	"System.IterationPath",
+	"Microsoft.VSTS.Scheduling.StoryPoints",
+	"Microsoft.VSTS.Scheduling.Effort",
+	"Microsoft.VSTS.Scheduling.RemainingWork",
+}
+
+// Matches an existing ASOF clause at the end of a WIQL query
+var asOfClausePattern = regexp.MustCompile(`(?is)\s+ASOF\s+'[^']*'\s*$`)
+
+// applyAsOfClause sets the ASOF clause of a WIQL query, replacing any existing one
+func applyAsOfClause(query string, asOf time.Time) string {
+	query = asOfClausePattern.ReplaceAllString(strings.TrimSpace(query), "")
+	return fmt.Sprintf("%s ASOF '%s'", query, asOf.UTC().Format(time.RFC3339))
+}
+
+// parseAsOfArg reads an optional as_of argument
+func parseAsOfArg(request mcp.CallToolRequest) (*azuredevops.Time, error) {
+	asOfStr, _ := request.Params.Arguments["as_of"].(string)
+	if asOfStr == "" {
+		return nil, nil
+	}
+	asOf, err := parseDateArg(asOfStr)
+	if err != nil {
+		return nil, err
+	}
+	return &azuredevops.Time{Time: asOf.UTC()}, nil
+}
+
+// queryWorkItemIDs runs a WIQL query, optionally as of a point in time, and returns the matching IDs
+func queryWorkItemIDs(ctx context.Context, query string, asOf *azuredevops.Time) ([]int, error) {
+	if asOf != nil {
+		query = applyAsOfClause(query, asOf.Time)
+	}
+
+	queryResult, err := workItemClient.QueryByWiql(ctx, workitemtracking.QueryByWiqlArgs{
+		Wiql: &workitemtracking.Wiql{
+			Query: &query,
+		},
+		Project: &config.Project,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	if queryResult.WorkItems != nil {
+		for _, ref := range *queryResult.WorkItems {
+			ids = append(ids, *ref.Id)
+		}
+	}
+	return ids, nil
+}
+
+// getWorkItemsAsOf fetches the given fields of work items in batches of 200
+func getWorkItemsAsOf(ctx context.Context, ids []int, fields []string, asOf *azuredevops.Time) (map[int]map[string]interface{}, error) {
+	items := make(map[int]map[string]interface{})
+	errorPolicy := workitemtracking.WorkItemErrorPolicyValues.Omit
+	for start := 0; start < len(ids); start += 200 {
+		batch := ids[start:min(start+200, len(ids))]
+		workItems, err := workItemClient.GetWorkItems(ctx, workitemtracking.GetWorkItemsArgs{
+			Ids:         &batch,
+			Project:     &config.Project,
+			Fields:      &fields,
+			AsOf:        asOf,
+			ErrorPolicy: &errorPolicy,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range *workItems {
+			if item.Id == nil {
+				continue
+			}
+			values := make(map[string]interface{})
+			if item.Fields != nil {
+				values = *item.Fields
+			}
+			items[*item.Id] = values
+		}
+	}
+	return items, nil
+}
+
+// Handler for comparing a query result between two points in time
+func handleCompareSnapshots(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := request.Params.Arguments["query"].(string)
+	fromStr := request.Params.Arguments["from"].(string)
+	toStr, _ := request.Params.Arguments["to"].(string)
+	fieldsStr, _ := request.Params.Arguments["fields"].(string)
+
+	from, err := parseDateArg(fromStr)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	to := time.Now().UTC()
+	if toStr != "" {
+		if to, err = parseDateArg(toStr); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if !from.Before(to) {
+		return mcp.NewToolResultError("'from' must be earlier than 'to'"), nil
+	}
+
+	fields := defaultSnapshotFields
+	if fieldsStr != "" {
+		fields = nil
+		for _, f := range strings.Split(fieldsStr, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				fields = append(fields, f)
+			}
+		}
+	}
+	if !containsString(fields, "System.Title") {
+		fields = append([]string{"System.Title"}, fields...)
+	}
+
+	fromAsOf := &azuredevops.Time{Time: from.UTC()}
+	toAsOf := &azuredevops.Time{Time: to.UTC()}
+
+	fromIDs, err := queryWorkItemIDs(ctx, query, fromAsOf)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to run query as of %s: %v", from.Format(time.RFC3339), err)), nil
+	}
+	toIDs, err := queryWorkItemIDs(ctx, query, toAsOf)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to run query as of %s: %v", to.Format(time.RFC3339), err)), nil
+	}
+
+	fromItems, err := getWorkItemsAsOf(ctx, fromIDs, fields, fromAsOf)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get work items: %v", err)), nil
+	}
+	toItems, err := getWorkItemsAsOf(ctx, toIDs, fields, toAsOf)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get work items: %v", err)), nil
+	}
+
+	var added, removed, changed []string
+	for _, id := range toIDs {
+		if _, ok := fromItems[id]; !ok {
+			title, _ := toItems[id]["System.Title"].(string)
+			added = append(added, fmt.Sprintf("- #%d %s", id, title))
+		}
+	}
+	for _, id := range fromIDs {
+		if _, ok := toItems[id]; !ok {
+			title, _ := fromItems[id]["System.Title"].(string)
+			removed = append(removed, fmt.Sprintf("- #%d %s", id, title))
+		}
+	}
+
+	sortedIDs := append([]int(nil), toIDs...)
+	sort.Ints(sortedIDs)
+	for _, id := range sortedIDs {
+		before, ok := fromItems[id]
+		if !ok {
+			continue
+		}
+		after := toItems[id]
+
+		var diffs []string
+		for _, field := range fields {
+			oldValue := formatFieldValue(before[field])
+			newValue := formatFieldValue(after[field])
+			if oldValue != newValue {
+				diffs = append(diffs, fmt.Sprintf("    %s: %s → %s", field, oldValue, newValue))
+			}
+		}
+		if len(diffs) > 0 {
+			title, _ := after["System.Title"].(string)
+			changed = append(changed, fmt.Sprintf("- #%d %s\n%s", id, title, strings.Join(diffs, "\n")))
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Snapshot comparison from %s to %s\n",
+		from.Format(time.RFC3339), to.Format(time.RFC3339)))
+	result.WriteString(fmt.Sprintf("Items then: %d, items now: %d\n", len(fromIDs), len(toIDs)))
+
+	writeSection := func(title string, lines []string) {
+		result.WriteString(fmt.Sprintf("\n%s (%d):\n", title, len(lines)))
+		if len(lines) == 0 {
+			result.WriteString("  (none)\n")
+			return
+		}
+		result.WriteString(strings.Join(lines, "\n"))
+		result.WriteString("\n")
+	}
+	writeSection("Scope added", added)
+	writeSection("Scope removed", removed)
+	writeSection("Changed", changed)
+
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
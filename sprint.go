@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -10,68 +9,86 @@ import (
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/core"
 )
 
-func handleGetCurrentSprint(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	team, _ := request.Params.Arguments["team"].(string)
+// teamIteration is a sprint as returned by the team settings API
+type teamIteration struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Attributes struct {
+		StartDate  *time.Time `json:"startDate"`
+		FinishDate *time.Time `json:"finishDate"`
+		TimeFrame  string     `json:"timeFrame"`
+	} `json:"attributes"`
+}
+
+// resolveTeam returns the team named in a tool call or the configured default team.
+// An empty result means the project's default team.
+func resolveTeam(team string) string {
+	team = strings.TrimSpace(team)
 	if team == "" {
-		team = config.Project + " Team" // Default team name
+		team = config.DefaultTeam
 	}
+	return team
+}
 
-	// Build the URL for the current iteration
-	baseURL := fmt.Sprintf("%s/%s/_apis/work/teamsettings/iterations",
-		config.OrganizationURL,
-		config.Project)
-
+// getTeamIterations lists a team's sprints, optionally filtered by time frame (current, future, past)
+func getTeamIterations(ctx context.Context, team, timeframe string) ([]teamIteration, error) {
 	queryParams := url.Values{}
-	queryParams.Add("$timeframe", "current")
+	if timeframe != "" {
+		queryParams.Add("$timeframe", timeframe)
+	}
 	queryParams.Add("api-version", "7.2-preview")
 
-	fullURL := fmt.Sprintf("%s?%s", baseURL, queryParams.Encode())
-
-	// Create request
-	req, err := http.NewRequest("GET", fullURL, nil)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to create request: %v", err)), nil
+	// Without a team segment the API uses the project's default team
+	fullURL := fmt.Sprintf("%s/%s", config.OrganizationURL, url.PathEscape(config.Project))
+	if team != "" {
+		fullURL += "/" + url.PathEscape(team)
 	}
+	fullURL += "/_apis/work/teamsettings/iterations?" + queryParams.Encode()
 
-	// Add authentication
-	req.SetBasicAuth("", config.PersonalAccessToken)
-
-	// Send request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get current sprint: %v", err)), nil
+	var sprintResponse struct {
+		Value []teamIteration `json:"value"`
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get current sprint. Status: %d", resp.StatusCode)), nil
+	if err := doAzureDevOpsJSON(ctx, http.MethodGet, fullURL, nil, &sprintResponse); err != nil {
+		if team != "" {
+			return nil, fmt.Errorf("%v (check the team name with list_teams)", err)
+		}
+		return nil, err
 	}
+	return sprintResponse.Value, nil
+}
 
-	// Parse response
-	var sprintResponse struct {
-		Value []struct {
-			Name      string    `json:"name"`
-			StartDate time.Time `json:"startDate"`
-			EndDate   time.Time `json:"finishDate"`
-		} `json:"value"`
+// formatIterationDate formats a sprint start or end date, which may not be set
+func formatIterationDate(date *time.Time) string {
+	if date == nil {
+		return "not set"
 	}
+	return date.Format("2006-01-02")
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&sprintResponse); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse response: %v", err)), nil
+func handleGetCurrentSprint(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	team, _ := request.Params.Arguments["team"].(string)
+	team = resolveTeam(team)
+
+	sprints, err := getTeamIterations(ctx, team, "current")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get current sprint: %v", err)), nil
 	}
 
-	if len(sprintResponse.Value) == 0 {
+	if len(sprints) == 0 {
 		return mcp.NewToolResultText("No active sprint found"), nil
 	}
 
-	sprint := sprintResponse.Value[0]
+	sprint := sprints[0]
 	result := fmt.Sprintf("Current Sprint: %s\nStart Date: %s\nEnd Date: %s",
 		sprint.Name,
-		sprint.StartDate.Format("2006-01-02"),
-		sprint.EndDate.Format("2006-01-02"))
+		formatIterationDate(sprint.Attributes.StartDate),
+		formatIterationDate(sprint.Attributes.FinishDate))
+	if team != "" {
+		result += "\nTeam: " + team
+	}
 
 	return mcp.NewToolResultText(result), nil
 }
@@ -79,64 +96,102 @@ func handleGetCurrentSprint(ctx context.Context, request mcp.CallToolRequest) (*
 func handleGetSprints(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	team, _ := request.Params.Arguments["team"].(string)
 	includeCompleted, _ := request.Params.Arguments["include_completed"].(bool)
-	if team == "" {
-		team = config.Project + " Team"
-	}
-
-	// Build the URL for iterations
-	baseURL := fmt.Sprintf("%s/%s/_apis/work/teamsettings/iterations",
-		config.OrganizationURL,
-		config.Project)
+	team = resolveTeam(team)
 
-	queryParams := url.Values{}
+	timeframe := ""
 	if !includeCompleted {
-		queryParams.Add("$timeframe", "current,future")
+		timeframe = "current,future"
+	}
+	sprints, err := getTeamIterations(ctx, team, timeframe)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get sprints: %v", err)), nil
 	}
-	queryParams.Add("api-version", "7.2-preview")
 
-	fullURL := fmt.Sprintf("%s?%s", baseURL, queryParams.Encode())
+	var results []string
+	for _, sprint := range sprints {
+		results = append(results, fmt.Sprintf("Sprint: %s\nStart: %s\nEnd: %s\n---",
+			sprint.Name,
+			formatIterationDate(sprint.Attributes.StartDate),
+			formatIterationDate(sprint.Attributes.FinishDate)))
+	}
 
-	req, err := http.NewRequest("GET", fullURL, nil)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to create request: %v", err)), nil
+	if len(results) == 0 {
+		return mcp.NewToolResultText("No sprints found"), nil
 	}
 
-	req.SetBasicAuth("", config.PersonalAccessToken)
+	return mcp.NewToolResultText(strings.Join(results, "\n")), nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// getCurrentSprintName returns the name of a team's current iteration, or "" when none is active
+func getCurrentSprintName(ctx context.Context, team string) (string, error) {
+	sprints, err := getTeamIterations(ctx, resolveTeam(team), "current")
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get sprints: %v", err)), nil
+		return "", fmt.Errorf("failed to get current sprint: %v", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get sprints. Status: %d", resp.StatusCode)), nil
+	if len(sprints) == 0 {
+		return "", nil
 	}
+	return sprints[0].Name, nil
+}
 
-	var sprintResponse struct {
-		Value []struct {
-			Name      string    `json:"name"`
-			StartDate time.Time `json:"startDate"`
-			EndDate   time.Time `json:"finishDate"`
-		} `json:"value"`
-	}
+// Handler for listing the teams of the project
+func handleListTeams(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	mine, _ := request.Params.Arguments["mine"].(bool)
 
-	if err := json.NewDecoder(resp.Body).Decode(&sprintResponse); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse response: %v", err)), nil
+	project, err := coreClient.GetProject(ctx, core.GetProjectArgs{
+		ProjectId: &config.Project,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get project: %v", err)), nil
+	}
+	defaultTeam := ""
+	if project.DefaultTeam != nil && project.DefaultTeam.Name != nil {
+		defaultTeam = *project.DefaultTeam.Name
 	}
 
-	var results []string
-	for _, sprint := range sprintResponse.Value {
-		results = append(results, fmt.Sprintf("Sprint: %s\nStart: %s\nEnd: %s\n---",
-			sprint.Name,
-			sprint.StartDate.Format("2006-01-02"),
-			sprint.EndDate.Format("2006-01-02")))
+	var teams []core.WebApiTeam
+	top := 100
+	for skip := 0; ; skip += top {
+		page, err := coreClient.GetTeams(ctx, core.GetTeamsArgs{
+			ProjectId: &config.Project,
+			Mine:      &mine,
+			Top:       &top,
+			Skip:      &skip,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list teams: %v", err)), nil
+		}
+		teams = append(teams, *page...)
+		if len(*page) < top {
+			break
+		}
 	}
 
-	if len(results) == 0 {
-		return mcp.NewToolResultText("No sprints found"), nil
+	if len(teams) == 0 {
+		return mcp.NewToolResultText("No teams found"), nil
 	}
 
-	return mcp.NewToolResultText(strings.Join(results, "\n")), nil
+	var results []string
+	for _, team := range teams {
+		if team.Name == nil {
+			continue
+		}
+		line := "- " + *team.Name
+		var notes []string
+		if *team.Name == defaultTeam {
+			notes = append(notes, "project default")
+		}
+		if config.DefaultTeam != "" && strings.EqualFold(*team.Name, config.DefaultTeam) {
+			notes = append(notes, "configured default")
+		}
+		if len(notes) > 0 {
+			line += " (" + strings.Join(notes, ", ") + ")"
+		}
+		if team.Description != nil && *team.Description != "" {
+			line += ": " + *team.Description
+		}
+		results = append(results, line)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Teams in project %s:\n%s", config.Project, strings.Join(results, "\n"))), nil
 }
@@ -84,6 +84,14 @@ func handleCreateFromTemplate(ctx context.Context, request mcp.CallToolRequest)
 
 	// Override with provided field values
 	for field, value := range fieldValues {
+		if assignedTo, ok := value.(string); ok && isAssignedToField(field) {
+			resolved, err := resolveAssignedTo(ctx, assignedTo)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			value = resolved
+		}
+		value = convertFieldValue(field, value, "markdown")
 		operations = append(operations, webapi.JsonPatchOperation{
 			Op:    &webapi.OperationValues.Add,
 			Path:  stringPtr("/fields/" + field),
@@ -9,16 +9,25 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
 )
 
+// Cached wikis of the project, refreshed by get_available_wikis, and names of their repositories
+var (
+	wikiCacheMutex      sync.Mutex
+	wikiCache           []*wiki.WikiV2
+	wikiRepositoryNames = make(map[string]string)
+)
+
 func addWikiTools(s *server.MCPServer) {
 	// Wiki Page Management
 	manageWikiTool := mcp.NewTool("manage_wiki_page",
-		mcp.WithDescription("Create or update a wiki page"),
+		mcp.WithDescription("Create or update a wiki page without overwriting concurrent edits"),
 		mcp.WithString("path",
 			mcp.Required(),
 			mcp.Description("Path of the wiki page"),
@@ -27,6 +36,21 @@ func addWikiTools(s *server.MCPServer) {
 			mcp.Required(),
 			mcp.Description("Content of the wiki page in markdown format"),
 		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+		mcp.WithString("etag",
+			mcp.Description("Version (ETag) of the page your content is based on, as returned by get_wiki_page; the update is refused if the page changed since"),
+		),
+		mcp.WithString("base_content",
+			mcp.Description("Page content your changes are based on, used for the three-way diff and auto-merge on conflict"),
+		),
+		mcp.WithBoolean("auto_merge",
+			mcp.Description("On conflict, merge your changes with the current page when they don't overlap"),
+		),
 	)
 	s.AddTool(manageWikiTool, handleManageWikiPage)
 
@@ -40,9 +64,109 @@ func addWikiTools(s *server.MCPServer) {
 		mcp.WithBoolean("include_children",
 			mcp.Description("Whether to include child pages"),
 		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Tag or commit to read instead of a branch (see version_type)"),
+		),
+		mcp.WithString("version_type",
+			mcp.Description("How to interpret version (defaults to branch)"),
+			mcp.Enum("branch", "tag", "commit"),
+		),
 	)
 	s.AddTool(getWikiTool, handleGetWikiPage)
 
+	// Get Wiki Page Outline
+	wikiOutlineTool := mcp.NewTool("get_wiki_page_outline",
+		mcp.WithDescription("List the headings of a wiki page with their line ranges and anchors, and the page's ETag"),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the wiki page"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Tag or commit to read instead of a branch (see version_type)"),
+		),
+		mcp.WithString("version_type",
+			mcp.Description("How to interpret version (defaults to branch)"),
+			mcp.Enum("branch", "tag", "commit"),
+		),
+	)
+	s.AddTool(wikiOutlineTool, handleGetWikiPageOutline)
+
+	// Get Wiki Page Section
+	wikiSectionTool := mcp.NewTool("get_wiki_page_section",
+		mcp.WithDescription("Get one section of a wiki page, from its heading up to the next heading of the same or a higher level"),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the wiki page"),
+		),
+		mcp.WithString("section",
+			mcp.Required(),
+			mcp.Description("Heading path such as 'Setup > Linux' (enclosing headings may be left out) or an anchor such as '#linux'"),
+		),
+		mcp.WithBoolean("include_subsections",
+			mcp.Description("Include the section's sub-sections (defaults to true)"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Tag or commit to read instead of a branch (see version_type)"),
+		),
+		mcp.WithString("version_type",
+			mcp.Description("How to interpret version (defaults to branch)"),
+			mcp.Enum("branch", "tag", "commit"),
+		),
+	)
+	s.AddTool(wikiSectionTool, handleGetWikiPageSection)
+
+	// Update Wiki Page Section
+	updateWikiSectionTool := mcp.NewTool("update_wiki_page_section",
+		mcp.WithDescription("Replace, append to or insert next to one section of a wiki page, leaving the rest of the page untouched"),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the wiki page"),
+		),
+		mcp.WithString("section",
+			mcp.Description("Heading path such as 'Setup > Linux' or an anchor such as '#linux'; leave empty to append to the end of the page"),
+		),
+		mcp.WithString("operation",
+			mcp.Required(),
+			mcp.Description("replace: replace the section and its sub-sections (the heading is kept unless content starts with one); append: add content at the end of the section's own text, before its sub-sections; insert_before/insert_after: add content before or after the section"),
+			mcp.Enum("replace", "append", "insert_before", "insert_after"),
+		),
+		mcp.WithString("content",
+			mcp.Required(),
+			mcp.Description("Markdown content to write"),
+		),
+		mcp.WithString("etag",
+			mcp.Description("ETag of the page version you read, from get_wiki_page_outline or get_wiki_page_section; edits to a newer page are merged or reported as a conflict"),
+		),
+		mcp.WithBoolean("auto_merge",
+			mcp.Description("On conflict, merge your changes with the current page when they don't overlap"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(updateWikiSectionTool, handleUpdateWikiPageSection)
+
 	// List Wiki Pages
 	listWikiTool := mcp.NewTool("list_wiki_pages",
 		mcp.WithDescription("List wiki pages in a directory"),
@@ -52,12 +176,25 @@ func addWikiTools(s *server.MCPServer) {
 		mcp.WithBoolean("recursive",
 			mcp.Description("Whether to list pages recursively"),
 		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Tag or commit to read instead of a branch (see version_type)"),
+		),
+		mcp.WithString("version_type",
+			mcp.Description("How to interpret version (defaults to branch)"),
+			mcp.Enum("branch", "tag", "commit"),
+		),
 	)
 	s.AddTool(listWikiTool, handleListWikiPages)
 
 	// Search Wiki
 	searchWikiTool := mcp.NewTool("search_wiki",
-		mcp.WithDescription("Search wiki pages"),
+		mcp.WithDescription("Full-text search of wiki page content. Uses the Azure DevOps search service when available and otherwise scans page content. Returns ranked pages with highlighted snippets and line numbers."),
 		mcp.WithString("query",
 			mcp.Required(),
 			mcp.Description("Search query"),
@@ -65,14 +202,468 @@ func addWikiTools(s *server.MCPServer) {
 		mcp.WithString("path",
 			mcp.Description("Path to limit search to (optional)"),
 		),
+		mcp.WithNumber("top",
+			mcp.Description("Maximum number of pages to return (default 10)"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch); other branches are searched by scanning page content"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Tag or commit of a code wiki to search by scanning page content"),
+		),
+		mcp.WithString("version_type",
+			mcp.Description("How to interpret version (defaults to branch)"),
+			mcp.Enum("branch", "tag", "commit"),
+		),
 	)
 	s.AddTool(searchWikiTool, handleSearchWiki)
 
+	// Search Wiki Index
+	searchWikiIndexTool := mcp.NewTool("search_wiki_index",
+		mcp.WithDescription("Search a local BM25 index of the wiki and return the best matching passages with citations. The index is stored on disk and refreshed incrementally, re-reading only pages that changed."),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Search query"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Path to limit search to (optional)"),
+		),
+		mcp.WithNumber("top",
+			mcp.Description("Maximum number of passages to return (default 5)"),
+		),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Bring the index up to date before searching (defaults to true); an empty index is always built"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Tag or commit to index instead of a branch (see version_type)"),
+		),
+		mcp.WithString("version_type",
+			mcp.Description("How to interpret version (defaults to branch)"),
+			mcp.Enum("branch", "tag", "commit"),
+		),
+	)
+	s.AddTool(searchWikiIndexTool, handleSearchWikiIndex)
+
 	// Get Available Wikis
 	getWikisTool := mcp.NewTool("get_available_wikis",
 		mcp.WithDescription("Get information about available wikis"),
 	)
 	s.AddTool(getWikisTool, handleGetWikis)
+
+	// Move Wiki Page
+	moveWikiTool := mcp.NewTool("move_wiki_page",
+		mcp.WithDescription("Move, rename or reorder a wiki page together with its sub-pages, updating links to it in other pages"),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Current path of the wiki page"),
+		),
+		mcp.WithString("new_path",
+			mcp.Description("New path of the page; use the same parent folder to rename it"),
+		),
+		mcp.WithNumber("order",
+			mcp.Description("New position of the page among its siblings (0-based)"),
+		),
+		mcp.WithBoolean("update_links",
+			mcp.Description("Rewrite links to the moved pages in other pages (defaults to true)"),
+		),
+		mcp.WithString("comment",
+			mcp.Description("Optional comment for the wiki history"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(moveWikiTool, handleMoveWikiPage)
+
+	// Get Wiki Page History
+	wikiHistoryTool := mcp.NewTool("get_wiki_page_history",
+		mcp.WithDescription("List the commits that changed a wiki page, newest first, with author, date and message"),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the wiki page"),
+		),
+		mcp.WithNumber("top",
+			mcp.Description("Maximum number of commits to return (default 20)"),
+		),
+		mcp.WithNumber("skip",
+			mcp.Description("Number of commits to skip, for paging"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Tag or commit to read instead of a branch (see version_type)"),
+		),
+		mcp.WithString("version_type",
+			mcp.Description("How to interpret version (defaults to branch)"),
+			mcp.Enum("branch", "tag", "commit"),
+		),
+	)
+	s.AddTool(wikiHistoryTool, handleGetWikiPageHistory)
+
+	// Diff Wiki Page
+	diffWikiTool := mcp.NewTool("diff_wiki_page",
+		mcp.WithDescription("Show a unified diff of a wiki page between two versions, or between a version and the current content. Without versions, shows the most recent change."),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the wiki page"),
+		),
+		mcp.WithString("from_version",
+			mcp.Description("Commit ID to compare from, as listed by get_wiki_page_history (defaults to the commit before the latest change)"),
+		),
+		mcp.WithString("to_version",
+			mcp.Description("Commit ID to compare to, or 'current' for the current content (defaults to the latest change when from_version is omitted, otherwise current)"),
+		),
+		mcp.WithNumber("context",
+			mcp.Description("Number of unchanged lines shown around each change (default 3)"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Tag or commit to read instead of a branch (see version_type)"),
+		),
+		mcp.WithString("version_type",
+			mcp.Description("How to interpret version (defaults to branch)"),
+			mcp.Enum("branch", "tag", "commit"),
+		),
+	)
+	s.AddTool(diffWikiTool, handleDiffWikiPage)
+
+	// Delete Wiki Page
+	deleteWikiTool := mcp.NewTool("delete_wiki_page",
+		mcp.WithDescription("Delete a wiki page, listing what would be deleted unless confirm is set"),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the wiki page to delete"),
+		),
+		mcp.WithBoolean("recursive",
+			mcp.Description("Also delete the page's sub-pages; required when it has any"),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("Must be true to actually delete; otherwise the pages that would be deleted are listed"),
+		),
+		mcp.WithString("comment",
+			mcp.Description("Optional comment for the wiki history"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(deleteWikiTool, handleDeleteWikiPage)
+
+	// Export Wiki
+	exportWikiTool := mcp.NewTool("export_wiki",
+		mcp.WithDescription("Export wiki pages to a local directory as markdown files in the wiki repository layout: one file per page, a folder per page with sub-pages, .order files and referenced attachments under .attachments"),
+		mcp.WithString("directory",
+			mcp.Required(),
+			mcp.Description("Directory to export to, relative to or inside AZURE_DEVOPS_DOWNLOAD_DIR; existing files are overwritten"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Page to export with its sub-pages (defaults to the whole wiki)"),
+		),
+		mcp.WithBoolean("include_attachments",
+			mcp.Description("Also export attachments the pages link to (defaults to true)"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Tag or commit to read instead of a branch (see version_type)"),
+		),
+		mcp.WithString("version_type",
+			mcp.Description("How to interpret version (defaults to branch)"),
+			mcp.Enum("branch", "tag", "commit"),
+		),
+	)
+	s.AddTool(exportWikiTool, handleExportWiki)
+
+	// Import Wiki
+	importWikiTool := mcp.NewTool("import_wiki",
+		mcp.WithDescription("Import a local directory of markdown files (in the layout written by export_wiki) into the wiki, creating, updating, moving, reordering and deleting pages. Returns a dry-run plan unless confirm is true."),
+		mcp.WithString("directory",
+			mcp.Required(),
+			mcp.Description("Directory to import, relative to or inside AZURE_DEVOPS_UPLOAD_DIR"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Wiki path the top of the directory maps to (defaults to the wiki root)"),
+		),
+		mcp.WithBoolean("delete_missing",
+			mcp.Description("Delete wiki pages below path that have no local file; pages whose content reappears under a new local path are moved instead. Without a .order file at the top of the directory, as in an exported subtree, only pages under the directory's top-level entries are considered"),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("Apply the plan; without it only the plan is returned"),
+		),
+		mcp.WithString("comment",
+			mcp.Description("Optional comment for the wiki history of moves and deletes"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(importWikiTool, handleImportWiki)
+
+	// Upload Wiki Attachment
+	uploadWikiAttachmentTool := mcp.NewTool("upload_wiki_attachment",
+		mcp.WithDescription("Upload a file, such as a diagram, to the wiki's attachments and return the markdown to embed it in a page"),
+		mcp.WithString("file_name",
+			mcp.Description("Name of the attachment (defaults to the name of file_path)"),
+		),
+		mcp.WithString("content",
+			mcp.Description("Base64 encoded content of the file"),
+		),
+		mcp.WithString("file_path",
+			mcp.Description("Path of a local file inside the configured upload directory"),
+		),
+		mcp.WithString("alt_text",
+			mcp.Description("Alternative text or link text for the returned markdown (defaults to the file name)"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(uploadWikiAttachmentTool, handleUploadWikiAttachment)
+
+	// List Wiki Attachments
+	listWikiAttachmentsTool := mcp.NewTool("list_wiki_attachments",
+		mcp.WithDescription("List the files in the wiki's attachments with their link paths"),
+		mcp.WithString("name_pattern",
+			mcp.Description("Only list attachments whose name matches this glob pattern (e.g. *.png)"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(listWikiAttachmentsTool, handleListWikiAttachments)
+
+	// Get Wiki Attachment
+	getWikiAttachmentTool := mcp.NewTool("get_wiki_attachment",
+		mcp.WithDescription("Download a wiki attachment: text files are returned inline, images as image content, other files as base64 or saved to the download directory"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Attachment name or its /.attachments/ link"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description("Maximum number of bytes of text to return inline (defaults to 102400)"),
+		),
+		mcp.WithBoolean("save",
+			mcp.Description("Save the file to the configured download directory instead of returning it"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(getWikiAttachmentTool, handleGetWikiAttachment)
+
+	// Check Wiki Links
+	checkWikiLinksTool := mcp.NewTool("check_wiki_links",
+		mcp.WithDescription("Crawl wiki pages and report broken page links, missing headings (anchors), missing attachments, mentions of work items that do not exist (#123) and orphan pages no other page links to"),
+		mcp.WithString("path",
+			mcp.Description("Only check pages under this path (links may still point anywhere in the wiki)"),
+		),
+		mcp.WithBoolean("check_work_items",
+			mcp.Description("Check that mentioned work items exist (defaults to true)"),
+		),
+		mcp.WithBoolean("include_orphans",
+			mcp.Description("Report pages that are not linked from any other page (defaults to true)"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+		mcp.WithString("version",
+			mcp.Description("Tag or commit to read instead of a branch (see version_type)"),
+		),
+		mcp.WithString("version_type",
+			mcp.Description("How to interpret version (defaults to branch)"),
+			mcp.Enum("branch", "tag", "commit"),
+		),
+	)
+	s.AddTool(checkWikiLinksTool, handleCheckWikiLinks)
+
+	// List Wiki Templates
+	listWikiTemplatesTool := mcp.NewTool("list_wiki_templates",
+		mcp.WithDescription("List the page templates available for create_wiki_page_from_template and the variables each uses"),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(listWikiTemplatesTool, handleListWikiTemplates)
+
+	// Create Wiki Page From Template
+	createFromWikiTemplateTool := mcp.NewTool("create_wiki_page_from_template",
+		mcp.WithDescription("Create a wiki page from a template, substituting {{title}}, {{author}}, {{date}}, {{datetime}}, {{year}}, {{sprint}}, {{work_items}}, {{work_item_ids}}, {{project}}, {{wiki}}, {{parent}}, {{path}} and custom variables"),
+		mcp.WithString("template",
+			mcp.Required(),
+			mcp.Description("Name of the template, as listed by list_wiki_templates"),
+		),
+		mcp.WithString("title",
+			mcp.Required(),
+			mcp.Description("Title of the new page, also used as its name"),
+		),
+		mcp.WithString("parent_path",
+			mcp.Description("Path of the existing page to create the page under (defaults to the wiki root)"),
+		),
+		mcp.WithString("work_items",
+			mcp.Description("Comma-separated IDs of related work items, listed with their titles in {{work_items}}"),
+		),
+		mcp.WithString("sprint",
+			mcp.Description("Value of {{sprint}} (defaults to the team's current sprint)"),
+		),
+		mcp.WithString("team",
+			mcp.Description("Team whose current sprint fills {{sprint}} (defaults to AZURE_DEVOPS_TEAM or the project's default team)"),
+		),
+		mcp.WithString("author",
+			mcp.Description("Value of {{author}} (defaults to the user the access token belongs to)"),
+		),
+		mcp.WithString("variables",
+			mcp.Description("JSON object of further variables, e.g. {\"status\": \"Proposed\"}; these override the built-in ones"),
+		),
+		mcp.WithBoolean("preview",
+			mcp.Description("Return the rendered page without creating it"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(createFromWikiTemplateTool, handleCreateWikiPageFromTemplate)
+
+	// Link Work Item To Wiki Page
+	linkWikiPageTool := mcp.NewTool("link_work_item_to_wiki_page",
+		mcp.WithDescription("Link a work item to a wiki page with a Wiki Page artifact link, or remove the link"),
+		mcp.WithNumber("work_item_id",
+			mcp.Required(),
+			mcp.Description("ID of the work item"),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the wiki page"),
+		),
+		mcp.WithString("operation",
+			mcp.Description("Operation to perform (defaults to add)"),
+			mcp.Enum("add", "remove"),
+		),
+		mcp.WithString("comment",
+			mcp.Description("Optional comment to store on the link"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(linkWikiPageTool, handleLinkWorkItemToWikiPage)
+
+	// Get Wiki Page Work Items
+	wikiPageWorkItemsTool := mcp.NewTool("get_wiki_page_work_items",
+		mcp.WithDescription("List the work items linked to a wiki page, optionally including its sub-pages"),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the wiki page"),
+		),
+		mcp.WithBoolean("include_subpages",
+			mcp.Description("Also list work items linked to the page's sub-pages"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(wikiPageWorkItemsTool, handleGetWikiPageWorkItems)
+
+	// Get Work Item Wiki Pages
+	workItemWikiPagesTool := mcp.NewTool("get_work_item_wiki_pages",
+		mcp.WithDescription("List the wiki pages a work item links to, optionally with their content"),
+		mcp.WithNumber("work_item_id",
+			mcp.Required(),
+			mcp.Description("ID of the work item"),
+		),
+		mcp.WithBoolean("include_content",
+			mcp.Description("Include the content of each linked page"),
+		),
+	)
+	s.AddTool(workItemWikiPagesTool, handleGetWorkItemWikiPages)
+
+	// Get Wiki Page Stats
+	wikiPageStatsTool := mcp.NewTool("get_wiki_page_stats",
+		mcp.WithDescription("Report page views over recent days together with each page's last edit, to find the least visited or most outdated pages of a wiki subtree."),
+		mcp.WithString("path",
+			mcp.Description("Path of the subtree to report on (defaults to the whole wiki)"),
+		),
+		mcp.WithNumber("days",
+			mcp.Description("Count views over this many days up to today, at most 30 (default 30)"),
+		),
+		mcp.WithString("from",
+			mcp.Description("Count views from this date (YYYY-MM-DD or RFC3339) instead of days, within the last 30 days"),
+		),
+		mcp.WithString("to",
+			mcp.Description("Count views up to this date, inclusive (defaults to today)"),
+		),
+		mcp.WithString("sort",
+			mcp.Description("Order of the report (defaults to least_visited)"),
+			mcp.Enum("least_visited", "most_visited", "most_outdated"),
+		),
+		mcp.WithNumber("stale_days",
+			mcp.Description("Only include pages not edited for at least this many days"),
+		),
+		mcp.WithNumber("top",
+			mcp.Description("Maximum number of pages to list (default 50)"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Name or ID of the wiki (defaults to the configured wiki or the project wiki)"),
+		),
+		mcp.WithString("branch",
+			mcp.Description("Branch of a code wiki (defaults to the wiki's first published branch)"),
+		),
+	)
+	s.AddTool(wikiPageStatsTool, handleGetWikiPageStats)
 }
 
 func handleManageWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -81,42 +672,36 @@ func handleManageWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mc
 	// Note: Comments are not supported by the Azure DevOps Wiki API
 	_, _ = request.Params.Arguments["comment"].(string)
 
-	// Get all available wikis for the project
-	wikis, err := getWikisForProject(ctx)
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get wikis: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
-
-	if len(wikis) == 0 {
-		return mcp.NewToolResultError("No wikis found for this project"), nil
+	versionDescriptor, err := wikiWriteVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	// Use the first wiki by default, or try to match by project name
-	wikiId := *wikis[0].Id
-	for _, wiki := range wikis {
-		if strings.Contains(*wiki.Name, config.Project) {
-			wikiId = *wiki.Id
-			break
-		}
+	eTag, _ := request.Params.Arguments["etag"].(string)
+	autoMerge, _ := request.Params.Arguments["auto_merge"].(bool)
+	var baseContent *string
+	if base, ok := request.Params.Arguments["base_content"].(string); ok {
+		baseContent = &base
 	}
 
-	// Convert wiki ID to the format expected by the API
-	wikiIdentifier := fmt.Sprintf("%s", wikiId)
-
-	_, err = wikiClient.CreateOrUpdatePage(ctx, wiki.CreateOrUpdatePageArgs{
-		WikiIdentifier: &wikiIdentifier,
-		Path:           &path,
-		Project:        &config.Project,
-		Parameters: &wiki.WikiPageCreateOrUpdateParameters{
-			Content: &content,
-		},
+	result, err := writeWikiPage(ctx, wikiPageWrite{
+		Wiki:        selectedWiki,
+		Path:        path,
+		Version:     versionDescriptor,
+		Content:     content,
+		ETag:        eTag,
+		BaseContent: baseContent,
+		AutoMerge:   autoMerge,
 	})
-
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to manage wiki page: %v", err)), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Successfully managed wiki page: %s", path)), nil
+	return mcp.NewToolResultText(result), nil
 }
 
 func handleGetWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -135,35 +720,15 @@ func handleGetWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 		recursionLevel = "oneLevel"
 	}
 
-	// Get all available wikis for the project
-	wikis, err := getWikisForProject(ctx)
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get wikis: %v", err)), nil
-	}
-
-	log.Printf("Found %d wikis for project", len(wikis))
-	for i, wiki := range wikis {
-		log.Printf("Wiki %d: %s (ID: %s)", i+1, *wiki.Name, *wiki.Id)
+		return mcp.NewToolResultError(err.Error()), nil
 	}
+	wikiId := selectedWiki.Id.String()
 
-	if len(wikis) == 0 {
-		return mcp.NewToolResultError("No wikis found for this project"), nil
-	}
-
-	// Use the first wiki by default
-	wikiId := *wikis[0].Id
-	
-	// Try to find a wiki with a name that matches or contains the project name
-	projectName := strings.Replace(config.Project, " ", "", -1)
-	projectName = strings.ToLower(projectName)
-	
-	for _, wiki := range wikis {
-		wikiName := strings.ToLower(*wiki.Name)
-		if strings.Contains(wikiName, projectName) || strings.Contains(wikiName, "documentation") {
-			wikiId = *wiki.Id
-			log.Printf("Selected wiki: %s (ID: %s)", *wiki.Name, wikiId)
-			break
-		}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	// Build the URL with query parameters
@@ -176,6 +741,7 @@ func handleGetWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 	queryParams.Add("path", path)
 	queryParams.Add("recursionLevel", recursionLevel)
 	queryParams.Add("includeContent", "true")
+	addWikiVersionParams(queryParams, versionDescriptor)
 	queryParams.Add("api-version", "7.2-preview")
 
 	fullURL := fmt.Sprintf("%s?%s", baseURL, queryParams.Encode())
@@ -212,10 +778,12 @@ func handleGetWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 
 	// Parse response
 	var wikiResponse struct {
-		Content  string `json:"content"`
-		SubPages []struct {
-			Path    string `json:"path"`
-			Content string `json:"content"`
+		Content     string `json:"content"`
+		GitItemPath string `json:"gitItemPath"`
+		SubPages    []struct {
+			Path        string `json:"path"`
+			Content     string `json:"content"`
+			GitItemPath string `json:"gitItemPath"`
 		} `json:"subPages"`
 	}
 
@@ -227,13 +795,23 @@ func handleGetWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 
 	// Format result
 	var result strings.Builder
-	result.WriteString(fmt.Sprintf("=== %s ===\n\n", path))
+	result.WriteString(fmt.Sprintf("=== %s ===\n", path))
+	if eTag := resp.Header.Get("ETag"); eTag != "" {
+		result.WriteString(fmt.Sprintf("Version (ETag): %s\n", eTag))
+	}
+	if wikiResponse.GitItemPath != "" {
+		result.WriteString(wikiRepositoryLocation(ctx, selectedWiki, versionDescriptor, wikiResponse.GitItemPath) + "\n")
+	}
+	result.WriteString("\n")
 	result.WriteString(wikiResponse.Content)
 
 	if includeChildren && len(wikiResponse.SubPages) > 0 {
 		result.WriteString("\n\nSub-pages:\n")
 		for _, subPage := range wikiResponse.SubPages {
 			result.WriteString(fmt.Sprintf("\n=== %s ===\n", subPage.Path))
+			if subPage.GitItemPath != "" {
+				result.WriteString(wikiRepositoryLocation(ctx, selectedWiki, versionDescriptor, subPage.GitItemPath) + "\n")
+			}
 			result.WriteString(subPage.Content)
 			result.WriteString("\n")
 		}
@@ -251,23 +829,15 @@ func handleListWikiPages(ctx context.Context, request mcp.CallToolRequest) (*mcp
 		recursionLevel = "full"
 	}
 
-	// Get all available wikis for the project
-	wikis, err := getWikisForProject(ctx)
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get wikis: %v", err)), nil
-	}
-
-	if len(wikis) == 0 {
-		return mcp.NewToolResultError("No wikis found for this project"), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
+	wikiId := selectedWiki.Id.String()
 
-	// Use the first wiki by default, or try to match by project name
-	wikiId := *wikis[0].Id
-	for _, wiki := range wikis {
-		if strings.Contains(*wiki.Name, config.Project) {
-			wikiId = *wiki.Id
-			break
-		}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	// Build the URL with query parameters
@@ -281,6 +851,7 @@ func handleListWikiPages(ctx context.Context, request mcp.CallToolRequest) (*mcp
 		queryParams.Add("path", path)
 	}
 	queryParams.Add("recursionLevel", recursionLevel)
+	addWikiVersionParams(queryParams, versionDescriptor)
 	queryParams.Add("api-version", "7.2-preview")
 
 	fullURL := fmt.Sprintf("%s?%s", baseURL, queryParams.Encode())
@@ -314,18 +885,12 @@ func handleListWikiPages(ctx context.Context, request mcp.CallToolRequest) (*mcp
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to list wiki pages. Status: %d", resp.StatusCode)), nil
 	}
 
-	// Parse response
-	var listResponse struct {
-		Value []struct {
-			Path       string `json:"path"`
-			RemotePath string `json:"remotePath"`
-			IsFolder   bool   `json:"isFolder"`
-		} `json:"value"`
-	}
+	// Parse response, the pages endpoint returns the page at path with its sub-pages nested
+	var rootPage wiki.WikiPage
 
 	log.Printf("Wiki API Response: %s", string(responseBody))
-	
-	if err := json.Unmarshal(responseBody, &listResponse); err != nil {
+
+	if err := json.Unmarshal(responseBody, &rootPage); err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse response: %v", err)), nil
 	}
 
@@ -337,22 +902,39 @@ func handleListWikiPages(ctx context.Context, request mcp.CallToolRequest) (*mcp
 	}
 	result.WriteString(fmt.Sprintf("Wiki pages%s:\n\n", locationText))
 
-	for _, item := range listResponse.Value {
-		prefix := "📄 "
-		if item.IsFolder {
-			prefix = "📁 "
+	isCodeWiki := selectedWiki.Type != nil && *selectedWiki.Type == wiki.WikiTypeValues.CodeWiki
+	if isCodeWiki {
+		result.WriteString(wikiRepositoryLocation(ctx, selectedWiki, versionDescriptor, "") + "\n\n")
+	}
+
+	var writePages func(pages []wiki.WikiPage)
+	writePages = func(pages []wiki.WikiPage) {
+		for _, item := range pages {
+			if item.Path == nil {
+				continue
+			}
+			prefix := "📄 "
+			if item.IsParentPage != nil && *item.IsParentPage {
+				prefix = "📁 "
+			}
+			line := prefix + *item.Path
+			if isCodeWiki && item.GitItemPath != nil {
+				line += fmt.Sprintf(" (%s)", *item.GitItemPath)
+			}
+			result.WriteString(line + "\n")
+			if item.SubPages != nil {
+				writePages(*item.SubPages)
+			}
 		}
-		result.WriteString(fmt.Sprintf("%s%s\n", prefix, item.Path))
+	}
+	if rootPage.SubPages != nil {
+		writePages(*rootPage.SubPages)
 	}
 
 	return mcp.NewToolResultText(result.String()), nil
 }
 
-func handleSearchWiki(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	query := request.Params.Arguments["query"].(string)
-	path, hasPath := request.Params.Arguments["path"].(string)
-
-	// Get all available wikis for the project
+func handleGetWikis(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	wikis, err := getWikisForProject(ctx)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get wikis: %v", err)), nil
@@ -362,141 +944,250 @@ func handleSearchWiki(ctx context.Context, request mcp.CallToolRequest) (*mcp.Ca
 		return mcp.NewToolResultError("No wikis found for this project"), nil
 	}
 
-	// Use the first wiki by default, or try to match by project name
-	wikiId := *wikis[0].Id
-	for _, wiki := range wikis {
-		if strings.Contains(*wiki.Name, config.Project) {
-			wikiId = *wiki.Id
-			break
-		}
+	// Refresh the cached list so newly created wikis can be selected
+	wikiCacheMutex.Lock()
+	wikiCache = wikis
+	wikiCacheMutex.Unlock()
+
+	defaultID := ""
+	if defaultWiki, err := resolveWiki(ctx, ""); err == nil {
+		defaultID = defaultWiki.Id.String()
 	}
 
-	// First, get all pages (potentially under the specified path)
-	baseURL := fmt.Sprintf("%s/%s/_apis/wiki/wikis/%s/pages",
-		config.OrganizationURL,
-		url.PathEscape(config.Project),
-		wikiId)
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Found %d wikis for project %s:\n\n", len(wikis), config.Project))
 
-	queryParams := url.Values{}
-	queryParams.Add("recursionLevel", "full")
-	if hasPath {
-		queryParams.Add("path", path)
+	for i, wiki := range wikis {
+		marker := ""
+		if wiki.Id.String() == defaultID {
+			marker = " (default)"
+		}
+		wikiType := ""
+		if wiki.Type != nil {
+			wikiType = string(*wiki.Type)
+		}
+		result.WriteString(fmt.Sprintf("%d. Wiki Name: %s%s\n   Wiki ID: %s\n   Type: %s\n",
+			i+1, *wiki.Name, marker, *wiki.Id, wikiType))
+		if wiki.MappedPath != nil && wikiType == "codeWiki" {
+			result.WriteString(fmt.Sprintf("   Repository: %s, folder %s\n", wikiRepositoryName(ctx, wiki), *wiki.MappedPath))
+		}
+		if wiki.Versions != nil && len(*wiki.Versions) > 0 {
+			var branches []string
+			for _, v := range *wiki.Versions {
+				if v.Version != nil {
+					branches = append(branches, *v.Version)
+				}
+			}
+			result.WriteString(fmt.Sprintf("   Published branches: %s\n", strings.Join(branches, ", ")))
+		}
+		result.WriteString("\n")
 	}
-	queryParams.Add("includeContent", "true")
-	queryParams.Add("api-version", "7.2-preview")
 
-	fullURL := fmt.Sprintf("%s?%s", baseURL, queryParams.Encode())
+	return mcp.NewToolResultText(result.String()), nil
+}
 
-	// Create request
-	req, err := http.NewRequest("GET", fullURL, nil)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to create request: %v", err)), nil
+// wikiArg reads the optional wiki argument of a wiki tool
+func wikiArg(request mcp.CallToolRequest) string {
+	wikiRef, _ := request.Params.Arguments["wiki"].(string)
+	return strings.TrimSpace(wikiRef)
+}
+
+// wikiVersionArg reads the branch, version and version_type arguments of a wiki tool.
+// Code wikis default to their first published branch; project wikis need no version.
+func wikiVersionArg(request mcp.CallToolRequest, w *wiki.WikiV2) (*git.GitVersionDescriptor, error) {
+	branch, _ := request.Params.Arguments["branch"].(string)
+	version, _ := request.Params.Arguments["version"].(string)
+	versionType, _ := request.Params.Arguments["version_type"].(string)
+	isCodeWiki := w.Type != nil && *w.Type == wiki.WikiTypeValues.CodeWiki
+
+	if branch != "" && version != "" {
+		return nil, fmt.Errorf("pass either branch or version, not both")
+	}
+	if branch != "" {
+		version, versionType = branch, "branch"
+	}
+	if version == "" {
+		if !isCodeWiki || w.Versions == nil || len(*w.Versions) == 0 {
+			return nil, nil
+		}
+		published := (*w.Versions)[0]
+		return &published, nil
+	}
+	if versionType == "" {
+		versionType = "branch"
+	}
+	version = strings.TrimPrefix(version, "refs/heads/")
+
+	// Code wikis can only be browsed on the branches they are published from
+	if isCodeWiki && versionType == "branch" && w.Versions != nil {
+		var published []string
+		for _, v := range *w.Versions {
+			if v.Version == nil {
+				continue
+			}
+			name := strings.TrimPrefix(*v.Version, "refs/heads/")
+			if strings.EqualFold(name, version) {
+				return &git.GitVersionDescriptor{Version: &name, VersionType: &git.GitVersionTypeValues.Branch}, nil
+			}
+			published = append(published, name)
+		}
+		return nil, fmt.Errorf("branch %s is not published in wiki %s, published branches: %s", version, *w.Name, strings.Join(published, ", "))
 	}
 
-	// Add authentication
-	req.SetBasicAuth("", config.PersonalAccessToken)
+	gitVersionType := git.GitVersionType(versionType)
+	return &git.GitVersionDescriptor{Version: &version, VersionType: &gitVersionType}, nil
+}
 
-	// Send request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// wikiWriteVersionArg reads the version arguments of a tool that changes the wiki. Pages can
+// only be written to a branch, not to a commit or tag.
+func wikiWriteVersionArg(request mcp.CallToolRequest, w *wiki.WikiV2) (*git.GitVersionDescriptor, error) {
+	versionDescriptor, err := wikiVersionArg(request, w)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to search wiki: %v", err)), nil
+		return nil, err
 	}
-	defer resp.Body.Close()
+	if versionDescriptor != nil && versionDescriptor.VersionType != nil && *versionDescriptor.VersionType != git.GitVersionTypeValues.Branch {
+		return nil, fmt.Errorf("version_type %s is read-only, changes can only be made on a branch", *versionDescriptor.VersionType)
+	}
+	return versionDescriptor, nil
+}
 
-	// Read the response body
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to read response body: %v", err)), nil
+// addWikiVersionParams adds a version descriptor to a pages API query
+func addWikiVersionParams(queryParams url.Values, versionDescriptor *git.GitVersionDescriptor) {
+	if versionDescriptor == nil || versionDescriptor.Version == nil {
+		return
+	}
+	queryParams.Add("versionDescriptor.version", *versionDescriptor.Version)
+	if versionDescriptor.VersionType != nil {
+		queryParams.Add("versionDescriptor.versionType", string(*versionDescriptor.VersionType))
 	}
+}
 
-	if resp.StatusCode != http.StatusOK {
-		// Log error details
-		log.Printf("Wiki API Error - Status: %d, Response: %s", resp.StatusCode, string(responseBody))
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to search wiki. Status: %d", resp.StatusCode)), nil
+// wikiRepositoryName returns the name of the repository backing a wiki, falling back to its ID
+func wikiRepositoryName(ctx context.Context, w *wiki.WikiV2) string {
+	if w.RepositoryId == nil {
+		return ""
 	}
+	repositoryID := w.RepositoryId.String()
 
-	// Parse response
-	var searchResponse struct {
-		Count int `json:"count"`
-		Results []struct {
-			FileName    string `json:"fileName"`
-			Path        string `json:"path"`
-			MatchCount  int    `json:"hitCount"`
-			Repository  struct {
-				ID string `json:"id"`
-			} `json:"repository"`
-			Hits []struct {
-				Content    string `json:"content"`
-				LineNumber int    `json:"startLine"`
-			} `json:"hits"`
-		} `json:"results"`
-	}
-
-	log.Printf("Wiki API Search Response: %s", string(responseBody))
-	
-	if err := json.Unmarshal(responseBody, &searchResponse); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse response: %v", err)), nil
+	wikiCacheMutex.Lock()
+	name, ok := wikiRepositoryNames[repositoryID]
+	wikiCacheMutex.Unlock()
+	if ok {
+		return name
 	}
 
-	// Search through the pages
-	var results []string
-	queryLower := strings.ToLower(query)
-	for _, page := range searchResponse.Results {
-		if strings.Contains(strings.ToLower(page.FileName), queryLower) {
-			// Extract a snippet of context around the match
-			contentLower := strings.ToLower(page.FileName)
-			index := strings.Index(contentLower, queryLower)
-			start := 0
-			if index > 100 {
-				start = index - 100
-			}
-			end := len(page.FileName)
-			if index+len(query)+100 < len(page.FileName) {
-				end = index + len(query) + 100
-			}
+	repo, err := gitClient.GetRepository(ctx, git.GetRepositoryArgs{
+		RepositoryId: &repositoryID,
+		Project:      &config.Project,
+	})
+	if err != nil || repo.Name == nil {
+		return repositoryID
+	}
 
-			snippet := page.FileName[start:end]
-			if start > 0 {
-				snippet = "..." + snippet
-			}
-			if end < len(page.FileName) {
-				snippet = snippet + "..."
-			}
+	wikiCacheMutex.Lock()
+	wikiRepositoryNames[repositoryID] = *repo.Name
+	wikiCacheMutex.Unlock()
+	return *repo.Name
+}
 
-			results = append(results, fmt.Sprintf("Page: %s\nMatch: %s\n---\n", page.Path, snippet))
+// wikiRepositoryLocation describes where a page lives in the wiki's backing repository
+func wikiRepositoryLocation(ctx context.Context, w *wiki.WikiV2, versionDescriptor *git.GitVersionDescriptor, gitItemPath string) string {
+	location := "Repository: " + wikiRepositoryName(ctx, w)
+	if versionDescriptor != nil && versionDescriptor.Version != nil {
+		versionType := "branch"
+		if versionDescriptor.VersionType != nil {
+			versionType = string(*versionDescriptor.VersionType)
 		}
+		location += fmt.Sprintf(", %s %s", versionType, *versionDescriptor.Version)
 	}
-
-	if len(results) == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("No matches found for '%s'", query)), nil
+	if w.MappedPath != nil && gitItemPath == "" {
+		location += ", folder " + *w.MappedPath
+	}
+	if gitItemPath != "" {
+		location += ", path " + gitItemPath
 	}
+	return location
+}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Found %d matches:\n\n%s", len(results), strings.Join(results, "\n"))), nil
+// describeWikis lists wikis as "name (id)" for error messages
+func describeWikis(wikis []*wiki.WikiV2) string {
+	var names []string
+	for _, w := range wikis {
+		names = append(names, fmt.Sprintf("%s (%s)", *w.Name, w.Id))
+	}
+	return strings.Join(names, ", ")
 }
 
-func handleGetWikis(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	wikis, err := getWikisForProject(ctx)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get wikis: %v", err)), nil
+// resolveWiki picks the wiki named by ref (name or ID). Without ref it uses the configured
+// default wiki, then the only wiki, then the project wiki.
+func resolveWiki(ctx context.Context, ref string) (*wiki.WikiV2, error) {
+	wikiCacheMutex.Lock()
+	wikis := wikiCache
+	wikiCacheMutex.Unlock()
+
+	// A cached list may predate a wiki created since, so a miss reloads it once
+	reloaded := false
+	reload := func() error {
+		loaded, err := getWikisForProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get wikis: %v", err)
+		}
+		wikiCacheMutex.Lock()
+		wikiCache = loaded
+		wikiCacheMutex.Unlock()
+		wikis, reloaded = loaded, true
+		return nil
 	}
 
+	if wikis == nil {
+		if err := reload(); err != nil {
+			return nil, err
+		}
+	}
+	if len(wikis) == 0 && !reloaded {
+		if err := reload(); err != nil {
+			return nil, err
+		}
+	}
 	if len(wikis) == 0 {
-		return mcp.NewToolResultError("No wikis found for this project"), nil
+		return nil, fmt.Errorf("no wikis found for this project")
 	}
 
-	var result strings.Builder
-	result.WriteString(fmt.Sprintf("Found %d wikis for project %s:\n\n", len(wikis), config.Project))
-
-	for i, wiki := range wikis {
-		result.WriteString(fmt.Sprintf("%d. Wiki Name: %s\n   Wiki ID: %s\n\n",
-			i+1, *wiki.Name, *wiki.Id))
+	if ref == "" {
+		ref = config.DefaultWiki
+	}
+	if ref != "" {
+		for {
+			for _, w := range wikis {
+				if strings.EqualFold(w.Id.String(), ref) || strings.EqualFold(*w.Name, ref) {
+					return w, nil
+				}
+			}
+			if reloaded {
+				return nil, fmt.Errorf("wiki %q not found, available wikis: %s", ref, describeWikis(wikis))
+			}
+			if err := reload(); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	if len(wikis) == 1 {
+		return wikis[0], nil
+	}
+	var projectWikis []*wiki.WikiV2
+	for _, w := range wikis {
+		if w.Type != nil && *w.Type == wiki.WikiTypeValues.ProjectWiki {
+			projectWikis = append(projectWikis, w)
+		}
+	}
+	if len(projectWikis) == 1 {
+		return projectWikis[0], nil
+	}
+	return nil, fmt.Errorf("this project has several wikis, pass the wiki argument or set AZURE_DEVOPS_WIKI to one of: %s", describeWikis(wikis))
 }
 
-func getWikisForProject(ctx context.Context) ([]*wiki.Wiki, error) {
+func getWikisForProject(ctx context.Context) ([]*wiki.WikiV2, error) {
 	// Create request
 	wikiApiUrl := fmt.Sprintf("%s/%s/_apis/wiki/wikis?api-version=7.2-preview", 
 		config.OrganizationURL,
@@ -534,7 +1225,7 @@ func getWikisForProject(ctx context.Context) ([]*wiki.Wiki, error) {
 
 	// Parse response
 	var wikisResponse struct {
-		Value []*wiki.Wiki `json:"value"`
+		Value []*wiki.WikiV2 `json:"value"`
 	}
 	
 	log.Printf("Wiki API Response: %s", string(bodyBytes))
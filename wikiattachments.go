@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+)
+
+// wikiAttachmentLink returns the markdown that embeds or links a wiki attachment
+func wikiAttachmentLink(name, altText string, isImage bool) string {
+	target := "/.attachments/" + url.PathEscape(name)
+	if altText == "" {
+		altText = strings.TrimSuffix(name, path.Ext(name))
+	}
+	if isImage {
+		return fmt.Sprintf("![%s](%s)", altText, target)
+	}
+	return fmt.Sprintf("[%s](%s)", altText, target)
+}
+
+// wikiAttachmentName extracts an attachment name from a name, repository path or link target
+func wikiAttachmentName(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if i := strings.LastIndex(ref, ".attachments/"); i != -1 {
+		ref = ref[i+len(".attachments/"):]
+	}
+	if i := strings.IndexAny(ref, "?#"); i != -1 {
+		ref = ref[:i]
+	}
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+	return ref
+}
+
+// Handler for uploading a wiki attachment
+func handleUploadWikiAttachment(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	altText, _ := request.Params.Arguments["alt_text"].(string)
+
+	source, _, fileName, closeSource, err := openUploadSource(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	defer closeSource()
+	fileName = filepath.Base(fileName)
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiWriteVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(source, head)
+	contentType := imageContentType(fileName, head[:n])
+
+	// Attachment names are unique per wiki, so a taken name gets a short random suffix
+	name := fileName
+	for attempt := 0; ; attempt++ {
+		if _, err := source.Seek(0, io.SeekStart); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read file: %v", err)), nil
+		}
+		err = createWikiAttachment(ctx, selectedWiki, versionDescriptor, name, source)
+		if err == nil {
+			break
+		}
+		if azureStatusCode(err) != http.StatusConflict || attempt > 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to upload wiki attachment: %v", err)), nil
+		}
+		ext := path.Ext(fileName)
+		name = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(fileName, ext), uuid.New().String()[:8], ext)
+	}
+
+	snippet := wikiAttachmentLink(name, altText, inlineImageTypes[contentType])
+	result := fmt.Sprintf("Uploaded wiki attachment %s (%s) to wiki %s\n", name, contentType, *selectedWiki.Name)
+	if name != fileName {
+		result += fmt.Sprintf("An attachment named %s already existed, so the file was renamed.\n", fileName)
+	}
+	result += fmt.Sprintf("\nMarkdown to embed it in a page:\n%s", snippet)
+	return mcp.NewToolResultText(result), nil
+}
+
+// createWikiAttachment uploads a file, which the attachments API expects base64 encoded
+func createWikiAttachment(ctx context.Context, w *wiki.WikiV2, versionDescriptor *git.GitVersionDescriptor, name string, content io.Reader) error {
+	reader, writer := io.Pipe()
+	go func() {
+		encoder := base64.NewEncoder(base64.StdEncoding, writer)
+		_, err := io.Copy(encoder, content)
+		if err == nil {
+			err = encoder.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+	defer reader.Close()
+
+	wikiID := w.Id.String()
+	_, err := wikiClient.CreateAttachment(ctx, wiki.CreateAttachmentArgs{
+		UploadStream:      reader,
+		Project:           &config.Project,
+		WikiIdentifier:    &wikiID,
+		Name:              &name,
+		VersionDescriptor: versionDescriptor,
+	})
+	return err
+}
+
+// Handler for listing wiki attachments
+func handleListWikiAttachments(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namePattern, _ := request.Params.Arguments["name_pattern"].(string)
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	items, err := getWikiAttachmentItems(ctx, selectedWiki, versionDescriptor, true)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var lines []string
+	for _, item := range items {
+		name := path.Base(*item.Path)
+		if namePattern != "" {
+			if matched, err := filepath.Match(strings.ToLower(namePattern), strings.ToLower(name)); err != nil || !matched {
+				continue
+			}
+		}
+		line := fmt.Sprintf("- %s (%s)", name, "/.attachments/"+url.PathEscape(name))
+		if commit := item.LatestProcessedChange; commit != nil && commit.Author != nil {
+			line += fmt.Sprintf(", last changed by %s", stringOrEmpty(commit.Author.Name))
+			if commit.Author.Date != nil {
+				line += " on " + commit.Author.Date.Time.Format(time.RFC3339)
+			}
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+
+	if len(lines) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No attachments found in wiki %s", *selectedWiki.Name)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("%d attachment(s) in wiki %s:\n%s", len(lines), *selectedWiki.Name, strings.Join(lines, "\n"))), nil
+}
+
+// getWikiAttachmentItems lists the files in a wiki's attachments folder, optionally with the
+// commit that last changed each of them
+func getWikiAttachmentItems(ctx context.Context, w *wiki.WikiV2, versionDescriptor *git.GitVersionDescriptor, withLatestChange bool) ([]git.GitItem, error) {
+	if w.RepositoryId == nil {
+		return nil, fmt.Errorf("wiki %s has no backing repository", *w.Name)
+	}
+	repositoryID := w.RepositoryId.String()
+	scopePath := strings.TrimSuffix(wikiAttachmentGitPath(w, ""), "/")
+	recursionLevel := git.VersionControlRecursionTypeValues.OneLevel
+	items, err := gitClient.GetItems(ctx, git.GetItemsArgs{
+		RepositoryId:          &repositoryID,
+		Project:               &config.Project,
+		ScopePath:             &scopePath,
+		RecursionLevel:        &recursionLevel,
+		LatestProcessedChange: &withLatestChange,
+		VersionDescriptor:     versionDescriptor,
+	})
+	if err != nil {
+		// A wiki without attachments has no attachments folder
+		if azureStatusCode(err) == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list wiki attachments: %v", err)
+	}
+
+	var files []git.GitItem
+	for _, item := range *items {
+		if item.Path != nil && (item.IsFolder == nil || !*item.IsFolder) {
+			files = append(files, item)
+		}
+	}
+	return files, nil
+}
+
+// Handler for getting the content of a wiki attachment
+func handleGetWikiAttachment(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := wikiAttachmentName(request.Params.Arguments["name"].(string))
+	save, _ := request.Params.Arguments["save"].(bool)
+	maxBytes := defaultMaxTextBytes
+	if limit, ok := request.Params.Arguments["max_bytes"].(float64); ok && limit > 0 {
+		maxBytes = int(limit)
+	}
+	if name == "" || strings.Contains(name, "/") {
+		return mcp.NewToolResultError("Pass the attachment name or its /.attachments/ link"), nil
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	stream, err := readWikiRepositoryFile(ctx, selectedWiki, wikiAttachmentGitPath(selectedWiki, name), versionDescriptor)
+	if err != nil {
+		if azureStatusCode(err) == http.StatusNotFound {
+			return mcp.NewToolResultError(fmt.Sprintf("Wiki attachment %s not found", name)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to download wiki attachment: %v", err)), nil
+	}
+	defer stream.Close()
+
+	return attachmentContentResult(name, stream, save, maxBytes), nil
+}
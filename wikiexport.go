@@ -0,0 +1,665 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+)
+
+// Page titles are stored in file names the way the wiki repository stores them: spaces become
+// hyphens and hyphens and characters invalid in file names are escaped
+var wikiFileNameReplacer = strings.NewReplacer(
+	"%", "%25", "-", "%2D", " ", "-", ":", "%3A", "<", "%3C", ">", "%3E",
+	"*", "%2A", "?", "%3F", "|", "%7C", `"`, "%22", `\`, "%5C", "#", "%23",
+)
+
+// wikiFileName returns the file name, without extension, of a page title
+func wikiFileName(title string) string {
+	return wikiFileNameReplacer.Replace(title)
+}
+
+// wikiTitleFromFileName returns the page title stored in a file name without extension
+func wikiTitleFromFileName(name string) string {
+	title := strings.ReplaceAll(name, "-", " ")
+	if unescaped, err := url.PathUnescape(title); err == nil {
+		return unescaped
+	}
+	return title
+}
+
+// wikiAttachmentGitPath returns the repository path of a wiki attachment
+func wikiAttachmentGitPath(w *wiki.WikiV2, name string) string {
+	prefix := ""
+	if w.MappedPath != nil && *w.MappedPath != "/" {
+		prefix = strings.TrimSuffix(*w.MappedPath, "/")
+	}
+	return prefix + "/.attachments/" + name
+}
+
+// wikiAttachmentNames returns the names of the wiki attachments a page links to
+func wikiAttachmentNames(content string) []string {
+	var names []string
+	scanMarkdownLinks(content, func(_ int, target string) (string, bool) {
+		i := strings.Index(target, ".attachments/")
+		if i == -1 || urlSchemePattern.MatchString(target) {
+			return "", false
+		}
+		name := wikiAttachmentName(target)
+		if name != "" && !strings.Contains(name, "/") {
+			names = append(names, name)
+		}
+		return "", false
+	})
+	return names
+}
+
+// wikiPageFile returns the local file of a page, for a directory that holds the pages below base
+func wikiPageFile(directory, base, pagePath string) string {
+	return wikiPageDirectory(directory, base, pagePath) + ".md"
+}
+
+// wikiPageDirectory returns the local directory holding the sub-pages of a page
+func wikiPageDirectory(directory, base, pagePath string) string {
+	rel := strings.Trim(pagePath[len(base):], "/")
+	if rel == "" {
+		return directory
+	}
+	parts := []string{directory}
+	for _, segment := range strings.Split(rel, "/") {
+		parts = append(parts, wikiFileName(segment))
+	}
+	return filepath.Join(parts...)
+}
+
+// sortWikiPagesByOrder sorts sibling pages by their position in the wiki
+func sortWikiPagesByOrder(pages []wiki.WikiPage) {
+	sort.SliceStable(pages, func(i, j int) bool {
+		if pages[i].Order == nil || pages[j].Order == nil {
+			return pages[i].Order != nil
+		}
+		return *pages[i].Order < *pages[j].Order
+	})
+}
+
+// Handler for exporting wiki pages and their attachments to a local directory
+func handleExportWiki(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	directory := request.Params.Arguments["directory"].(string)
+	rootPath, _ := request.Params.Arguments["path"].(string)
+	includeAttachments := true
+	if value, ok := request.Params.Arguments["include_attachments"].(bool); ok {
+		includeAttachments = value
+	}
+
+	root := "/"
+	if rootPath != "" {
+		root = normalizeWikiPath(rootPath)
+	}
+	target, err := resolveDownloadPath(directory)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	pages, err := getWikiPagesWithContent(ctx, selectedWiki.Id.String(), root, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// An exported subtree keeps its top page, so the directory holds the pages below its parent
+	base := "/"
+	if root != "/" {
+		base = path.Dir(root)
+	}
+
+	written := 0
+	children := map[string][]wiki.WikiPage{}
+	attachments := map[string]bool{}
+	for _, page := range pages {
+		if page.Path == nil || *page.Path == "/" {
+			continue
+		}
+		content := ""
+		if page.Content != nil {
+			content = *page.Content
+		}
+		file := wikiPageFile(target, base, *page.Path)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to create directory: %v", err)), nil
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to write %s: %v", file, err)), nil
+		}
+		written++
+
+		parent := path.Dir(*page.Path)
+		children[parent] = append(children[parent], page)
+		for _, name := range wikiAttachmentNames(content) {
+			attachments[name] = true
+		}
+	}
+
+	// .order files keep the page order of every exported folder
+	for parent, siblings := range children {
+		if !isUnderWikiPath(parent, root) {
+			continue
+		}
+		sortWikiPagesByOrder(siblings)
+		var names []string
+		for _, sibling := range siblings {
+			names = append(names, wikiFileName(path.Base(*sibling.Path)))
+		}
+		orderFile := filepath.Join(wikiPageDirectory(target, base, parent), ".order")
+		if err := os.MkdirAll(filepath.Dir(orderFile), 0o755); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to create directory: %v", err)), nil
+		}
+		if err := os.WriteFile(orderFile, []byte(strings.Join(names, "\n")+"\n"), 0o644); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to write %s: %v", orderFile, err)), nil
+		}
+	}
+
+	var failed []string
+	savedAttachments := 0
+	if includeAttachments {
+		names := make([]string, 0, len(attachments))
+		for name := range attachments {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := exportWikiAttachment(ctx, selectedWiki, versionDescriptor, name, filepath.Join(target, ".attachments", name)); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+				continue
+			}
+			savedAttachments++
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Exported %d page(s) from wiki %s", written, *selectedWiki.Name))
+	if root != "/" {
+		result.WriteString(" under " + root)
+	}
+	result.WriteString(fmt.Sprintf(" to %s", target))
+	if includeAttachments {
+		result.WriteString(fmt.Sprintf(", with %d attachment(s)", savedAttachments))
+	}
+	result.WriteString("\n")
+	if len(failed) > 0 {
+		result.WriteString(fmt.Sprintf("\nCould not export %d attachment(s):\n- %s\n", len(failed), strings.Join(failed, "\n- ")))
+	}
+	if root != "/" {
+		result.WriteString(fmt.Sprintf("\nTo import the directory back, use path %s.\n", base))
+	}
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// exportWikiAttachment copies a wiki attachment to a local file
+func exportWikiAttachment(ctx context.Context, w *wiki.WikiV2, versionDescriptor *git.GitVersionDescriptor, name, file string) error {
+	reader, err := readWikiRepositoryFile(ctx, w, wikiAttachmentGitPath(w, name), versionDescriptor)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, reader)
+	return err
+}
+
+// localWikiPage is a page read from a local directory
+type localWikiPage struct {
+	Path     string
+	Content  string
+	Implicit bool // A folder of sub-pages without a page file
+}
+
+// wikiImportAction is one step of an import plan
+type wikiImportAction struct {
+	Kind     string // create, update, move, reorder or delete
+	Path     string
+	NewPath  string
+	Content  string
+	Children []string // Desired order of sub-pages, for reorder
+	Detail   string
+}
+
+// readLocalWiki reads page files and .order files from a directory whose top level maps to root
+func readLocalWiki(directory, root string) (map[string]*localWikiPage, map[string][]string, error) {
+	pages := map[string]*localWikiPage{}
+	orders := map[string][]string{}
+
+	pagePathOf := func(rel string) string {
+		pagePath := root
+		if rel == "." {
+			return pagePath
+		}
+		for _, segment := range strings.Split(filepath.ToSlash(rel), "/") {
+			pagePath = path.Join(pagePath, wikiTitleFromFileName(segment))
+		}
+		return pagePath
+	}
+
+	err := filepath.WalkDir(directory, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(directory, file)
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			// Skip .attachments, .git and other hidden folders
+			if rel != "." && strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			if rel != "." {
+				pagePath := pagePathOf(rel)
+				if pages[pagePath] == nil {
+					pages[pagePath] = &localWikiPage{Path: pagePath, Implicit: true}
+				}
+			}
+			return nil
+		}
+
+		switch {
+		case name == ".order":
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			var titles []string
+			for _, line := range splitLines(string(data)) {
+				if line = strings.TrimSpace(line); line != "" {
+					titles = append(titles, wikiTitleFromFileName(line))
+				}
+			}
+			orders[pagePathOf(filepath.Dir(rel))] = titles
+		case strings.HasSuffix(strings.ToLower(name), ".md"):
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			pagePath := pagePathOf(strings.TrimSuffix(rel, filepath.Ext(rel)))
+			pages[pagePath] = &localWikiPage{Path: pagePath, Content: string(data)}
+		}
+		return nil
+	})
+	return pages, orders, err
+}
+
+// sameWikiContent compares page content ignoring line endings and surrounding whitespace
+func sameWikiContent(a, b string) bool {
+	normalize := func(s string) string {
+		return strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
+	}
+	return normalize(a) == normalize(b)
+}
+
+// planWikiImport compares local pages with the wiki pages below root
+func planWikiImport(local map[string]*localWikiPage, orders map[string][]string, remote []wiki.WikiPage, root string, deleteMissing bool) ([]wikiImportAction, int) {
+	// Only a directory with a top-level .order holds all of root's sub-pages. Others, such as an
+	// exported subtree, hold some of them, and wiki pages outside those are left alone.
+	_, complete := orders[root]
+	var topLevel []string
+	for pagePath := range local {
+		if path.Dir(pagePath) == root {
+			topLevel = append(topLevel, pagePath)
+		}
+	}
+	inScope := func(pagePath string) bool {
+		if complete {
+			return true
+		}
+		for _, top := range topLevel {
+			if isUnderWikiPath(pagePath, top) {
+				return true
+			}
+		}
+		return false
+	}
+
+	remoteByPath := map[string]wiki.WikiPage{}
+	remoteChildren := map[string][]wiki.WikiPage{}
+	for _, page := range remote {
+		if page.Path == nil || *page.Path == root || *page.Path == "/" || !inScope(*page.Path) {
+			continue
+		}
+		remoteByPath[*page.Path] = page
+		parent := path.Dir(*page.Path)
+		remoteChildren[parent] = append(remoteChildren[parent], page)
+	}
+
+	var creates, updates, moves, deletes []wikiImportAction
+	unchanged := 0
+	for _, page := range local {
+		remotePage, exists := remoteByPath[page.Path]
+		switch {
+		case !exists:
+			creates = append(creates, wikiImportAction{Kind: "create", Path: page.Path, Content: page.Content,
+				Detail: fmt.Sprintf("%d line(s)", len(splitLines(page.Content)))})
+		case page.Implicit:
+			unchanged++
+		case remotePage.Content != nil && sameWikiContent(*remotePage.Content, page.Content):
+			unchanged++
+		default:
+			current := ""
+			if remotePage.Content != nil {
+				current = *remotePage.Content
+			}
+			added, removed := diffStat(splitLines(current), splitLines(page.Content))
+			updates = append(updates, wikiImportAction{Kind: "update", Path: page.Path, Content: page.Content,
+				Detail: fmt.Sprintf("+%d -%d line(s)", added, removed)})
+		}
+	}
+
+	if deleteMissing {
+		var missing []string
+		for remotePath := range remoteByPath {
+			if local[remotePath] == nil {
+				missing = append(missing, remotePath)
+			}
+		}
+		sort.Strings(missing)
+
+		// A missing page whose content reappears as a new local page was moved
+		moved := map[string]bool{}
+		for _, oldPath := range missing {
+			remotePage := remoteByPath[oldPath]
+			if remotePage.Content == nil || strings.TrimSpace(*remotePage.Content) == "" {
+				continue
+			}
+			for i, create := range creates {
+				if create.Kind == "create" && sameWikiContent(create.Content, *remotePage.Content) {
+					moves = append(moves, wikiImportAction{Kind: "move", Path: oldPath, NewPath: create.Path})
+					creates[i].Kind = ""
+					moved[oldPath] = true
+					break
+				}
+			}
+		}
+
+		// Sub-pages move with their parent, so drop moves their parent's move already covers
+		var outerMoves []wikiImportAction
+		for _, move := range moves {
+			covered := false
+			for _, other := range moves {
+				if other.Path != move.Path && isUnderWikiPath(move.Path, other.Path) &&
+					rebaseWikiPath(move.Path, other.Path, other.NewPath) == move.NewPath {
+					covered = true
+				}
+			}
+			if !covered {
+				outerMoves = append(outerMoves, move)
+			}
+		}
+		moves = outerMoves
+
+		for _, oldPath := range missing {
+			covered := moved[oldPath]
+			for _, move := range moves {
+				if isUnderWikiPath(oldPath, move.Path) {
+					covered = true
+				}
+			}
+			if !covered {
+				deletes = append(deletes, wikiImportAction{Kind: "delete", Path: oldPath})
+			}
+		}
+	}
+
+	var remaining []wikiImportAction
+	for _, create := range creates {
+		if create.Kind != "" {
+			remaining = append(remaining, create)
+		}
+	}
+	creates = remaining
+
+	// Parents before children when creating, children before parents when deleting
+	depth := func(p string) int { return strings.Count(p, "/") }
+	sort.SliceStable(creates, func(i, j int) bool {
+		if depth(creates[i].Path) != depth(creates[j].Path) {
+			return depth(creates[i].Path) < depth(creates[j].Path)
+		}
+		return creates[i].Path < creates[j].Path
+	})
+	sort.Slice(updates, func(i, j int) bool { return updates[i].Path < updates[j].Path })
+	sort.SliceStable(deletes, func(i, j int) bool { return depth(deletes[i].Path) > depth(deletes[j].Path) })
+
+	// Reorder folders whose .order differs from the wiki's order
+	var reorders []wikiImportAction
+	parents := make([]string, 0, len(orders))
+	for parent := range orders {
+		parents = append(parents, parent)
+	}
+	sort.Strings(parents)
+	for _, parent := range parents {
+		var desired []string
+		for _, title := range orders[parent] {
+			if child := path.Join(parent, title); local[child] != nil {
+				desired = append(desired, child)
+			}
+		}
+		siblings := remoteChildren[parent]
+		sortWikiPagesByOrder(siblings)
+		var current []string
+		for _, sibling := range siblings {
+			if local[*sibling.Path] != nil {
+				current = append(current, *sibling.Path)
+			}
+		}
+		if len(desired) > 1 && strings.Join(desired, "\n") != strings.Join(current, "\n") {
+			reorders = append(reorders, wikiImportAction{Kind: "reorder", Path: parent, Children: desired})
+		}
+	}
+
+	var plan []wikiImportAction
+	plan = append(plan, moves...)
+	plan = append(plan, creates...)
+	plan = append(plan, updates...)
+	plan = append(plan, reorders...)
+	plan = append(plan, deletes...)
+	return plan, unchanged
+}
+
+// diffStat counts the lines added and removed between two texts
+func diffStat(a, b []string) (int, int) {
+	added, removed := 0, 0
+	for _, op := range diffLines(a, b) {
+		switch op.Kind {
+		case '+':
+			added += op.BEnd - op.BStart
+		case '-':
+			removed += op.AEnd - op.AStart
+		}
+	}
+	return added, removed
+}
+
+// describeWikiImportAction formats a plan step
+func describeWikiImportAction(action wikiImportAction) string {
+	switch action.Kind {
+	case "move":
+		return fmt.Sprintf("move %s -> %s", action.Path, action.NewPath)
+	case "reorder":
+		titles := make([]string, 0, len(action.Children))
+		for _, child := range action.Children {
+			titles = append(titles, path.Base(child))
+		}
+		return fmt.Sprintf("reorder sub-pages of %s: %s", action.Path, strings.Join(titles, ", "))
+	}
+	line := action.Kind + " " + action.Path
+	if action.Detail != "" {
+		line += " (" + action.Detail + ")"
+	}
+	return line
+}
+
+// Handler for importing a local directory of markdown files into the wiki
+func handleImportWiki(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	directory := request.Params.Arguments["directory"].(string)
+	rootPath, _ := request.Params.Arguments["path"].(string)
+	deleteMissing, _ := request.Params.Arguments["delete_missing"].(bool)
+	confirm, _ := request.Params.Arguments["confirm"].(bool)
+	comment, _ := request.Params.Arguments["comment"].(string)
+
+	root := "/"
+	if rootPath != "" {
+		root = normalizeWikiPath(rootPath)
+	}
+	source, err := resolveUploadPath(directory)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if info, err := os.Stat(source); err != nil || !info.IsDir() {
+		return mcp.NewToolResultError(fmt.Sprintf("%s is not a directory", directory)), nil
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	wikiID := selectedWiki.Id.String()
+	versionDescriptor, err := wikiWriteVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	local, orders, err := readLocalWiki(source, root)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read %s: %v", source, err)), nil
+	}
+	if len(local) == 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("No markdown files found in %s", source)), nil
+	}
+
+	var remote []wiki.WikiPage
+	if root == "/" {
+		remote, err = getWikiPagesWithContent(ctx, wikiID, root, versionDescriptor)
+	} else if state, stateErr := getWikiPageState(ctx, wikiID, root, versionDescriptor); stateErr != nil {
+		err = stateErr
+	} else if state.Exists {
+		remote, err = getWikiPagesWithContent(ctx, wikiID, root, versionDescriptor)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	plan, unchanged := planWikiImport(local, orders, remote, root, deleteMissing)
+
+	var result strings.Builder
+	if len(plan) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Wiki %s is already up to date with %s (%d page(s) unchanged).", *selectedWiki.Name, source, unchanged)), nil
+	}
+
+	if !confirm {
+		result.WriteString(fmt.Sprintf("Import plan for %s into wiki %s at %s (%d page(s) unchanged):\n", source, *selectedWiki.Name, root, unchanged))
+		for _, action := range plan {
+			result.WriteString("- " + describeWikiImportAction(action) + "\n")
+		}
+		if !deleteMissing {
+			result.WriteString("\nWiki pages without a local file are kept; pass delete_missing=true to delete them or detect moves.\n")
+		}
+		result.WriteString("\nCall again with confirm=true to apply the plan.")
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	var done, failed []string
+	for _, action := range plan {
+		if err := applyWikiImportAction(ctx, selectedWiki, versionDescriptor, action, comment); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", describeWikiImportAction(action), err))
+			continue
+		}
+		done = append(done, describeWikiImportAction(action))
+	}
+
+	result.WriteString(fmt.Sprintf("Imported %s into wiki %s at %s: %d change(s) applied, %d page(s) unchanged.\n", source, *selectedWiki.Name, root, len(done), unchanged))
+	if len(done) > 0 {
+		result.WriteString("\nApplied:\n- " + strings.Join(done, "\n- ") + "\n")
+	}
+	if len(failed) > 0 {
+		result.WriteString("\nFailed:\n- " + strings.Join(failed, "\n- ") + "\n")
+	}
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// applyWikiImportAction carries out one step of an import plan
+func applyWikiImportAction(ctx context.Context, w *wiki.WikiV2, versionDescriptor *git.GitVersionDescriptor, action wikiImportAction, comment string) error {
+	wikiID := w.Id.String()
+	var commentPtr *string
+	if comment != "" {
+		commentPtr = &comment
+	}
+
+	switch action.Kind {
+	case "create", "update":
+		_, err := writeWikiPage(ctx, wikiPageWrite{
+			Wiki:    w,
+			Path:    action.Path,
+			Version: versionDescriptor,
+			Content: action.Content,
+		})
+		return err
+	case "move":
+		_, err := wikiClient.CreatePageMove(ctx, wiki.CreatePageMoveArgs{
+			PageMoveParameters: &wiki.WikiPageMoveParameters{Path: &action.Path, NewPath: &action.NewPath},
+			Project:            &config.Project,
+			WikiIdentifier:     &wikiID,
+			Comment:            commentPtr,
+			VersionDescriptor:  versionDescriptor,
+		})
+		return err
+	case "reorder":
+		for i, child := range action.Children {
+			order := i
+			_, err := wikiClient.CreatePageMove(ctx, wiki.CreatePageMoveArgs{
+				PageMoveParameters: &wiki.WikiPageMoveParameters{Path: &child, NewPath: &child, NewOrder: &order},
+				Project:            &config.Project,
+				WikiIdentifier:     &wikiID,
+				Comment:            commentPtr,
+				VersionDescriptor:  versionDescriptor,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "delete":
+		_, err := wikiClient.DeletePage(ctx, wiki.DeletePageArgs{
+			Project:           &config.Project,
+			WikiIdentifier:    &wikiID,
+			Path:              &action.Path,
+			Comment:           commentPtr,
+			VersionDescriptor: versionDescriptor,
+		})
+		return err
+	}
+	return fmt.Errorf("unknown action %s", action.Kind)
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+)
+
+func TestWikiFileName(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Getting Started", "Getting-Started"},
+		{"Follow-up", "Follow%2Dup"},
+		{"Q: A?", "Q%3A-A%3F"},
+		{"100%", "100%25"},
+		{"a|b", "a%7Cb"},
+		{"C#", "C%23"},
+	}
+	for _, tt := range tests {
+		got := wikiFileName(tt.title)
+		if got != tt.want {
+			t.Errorf("wikiFileName(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+		if title := wikiTitleFromFileName(got); title != tt.title {
+			t.Errorf("wikiTitleFromFileName(%q) = %q, want %q", got, title, tt.title)
+		}
+	}
+}
+
+func TestReadLocalWiki(t *testing.T) {
+	directory := t.TempDir()
+	files := map[string]string{
+		".order":                         "Home\nGetting-Started\nFolder\n",
+		"Home.md":                        "home",
+		"Getting-Started.md":             "start",
+		"Getting-Started/.order":         "Follow%2Dup\n",
+		"Getting-Started/Follow%2Dup.md": "follow",
+		"Folder/Page.md":                 "page",
+		".attachments/image.md":          "not a page",
+	}
+	for name, content := range files {
+		file := filepath.Join(directory, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pages, orders, err := readLocalWiki(directory, "/Parent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPages := map[string]localWikiPage{
+		"/Parent/Home":                      {Path: "/Parent/Home", Content: "home"},
+		"/Parent/Getting Started":           {Path: "/Parent/Getting Started", Content: "start"},
+		"/Parent/Getting Started/Follow-up": {Path: "/Parent/Getting Started/Follow-up", Content: "follow"},
+		"/Parent/Folder":                    {Path: "/Parent/Folder", Implicit: true},
+		"/Parent/Folder/Page":               {Path: "/Parent/Folder/Page", Content: "page"},
+	}
+	gotPages := map[string]localWikiPage{}
+	for pagePath, page := range pages {
+		gotPages[pagePath] = *page
+	}
+	if !reflect.DeepEqual(gotPages, wantPages) {
+		t.Errorf("readLocalWiki pages = %+v, want %+v", gotPages, wantPages)
+	}
+	wantOrders := map[string][]string{
+		"/Parent":                 {"Home", "Getting Started", "Folder"},
+		"/Parent/Getting Started": {"Follow-up"},
+	}
+	if !reflect.DeepEqual(orders, wantOrders) {
+		t.Errorf("readLocalWiki orders = %q, want %q", orders, wantOrders)
+	}
+}
+
+// testRemotePage builds a wiki page as returned by the pages API
+func testRemotePage(pagePath, content string, order int) wiki.WikiPage {
+	return wiki.WikiPage{Path: &pagePath, Content: &content, Order: &order}
+}
+
+// testLocalWiki builds local pages from contents keyed by path
+func testLocalWiki(pages map[string]string) map[string]*localWikiPage {
+	local := map[string]*localWikiPage{}
+	for pagePath, content := range pages {
+		local[pagePath] = &localWikiPage{Path: pagePath, Content: content}
+	}
+	return local
+}
+
+// planSteps formats the kind and paths of each step of a plan
+func planSteps(plan []wikiImportAction) []string {
+	var steps []string
+	for _, action := range plan {
+		step := action.Kind + " " + action.Path
+		if action.NewPath != "" {
+			step += " -> " + action.NewPath
+		}
+		if len(action.Children) > 0 {
+			step += ": " + strings.Join(action.Children, ", ")
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+func TestPlanWikiImport(t *testing.T) {
+	tests := []struct {
+		name          string
+		root          string
+		local         map[string]string
+		orders        map[string][]string
+		remote        []wiki.WikiPage
+		deleteMissing bool
+		want          []string
+		unchanged     int
+	}{
+		{
+			name:   "create update and unchanged",
+			root:   "/",
+			local:  map[string]string{"/A": "a\n", "/B": "new", "/C": "c"},
+			remote: []wiki.WikiPage{testRemotePage("/A", "a", 0), testRemotePage("/B", "old", 1)},
+			want:   []string{"create /C", "update /B"}, unchanged: 1,
+		},
+		{
+			name:   "moved page",
+			root:   "/",
+			local:  map[string]string{"/New": "body"},
+			orders: map[string][]string{"/": {"New"}},
+			remote: []wiki.WikiPage{testRemotePage("/Old", "body", 0)},
+			want:   []string{"move /Old -> /New"}, deleteMissing: true,
+		},
+		{
+			name:   "sub-page moves with its parent",
+			root:   "/",
+			local:  map[string]string{"/New": "parent", "/New/Child": "child"},
+			orders: map[string][]string{"/": {"New"}},
+			remote: []wiki.WikiPage{testRemotePage("/Old", "parent", 0), testRemotePage("/Old/Child", "child", 0)},
+			want:   []string{"move /Old -> /New"}, deleteMissing: true,
+		},
+		{
+			name:   "parents created first and deleted last",
+			root:   "/",
+			local:  map[string]string{"/A/B/C": "c", "/A": "a", "/A/B": "b"},
+			orders: map[string][]string{"/": {"A"}},
+			remote: []wiki.WikiPage{testRemotePage("/X", "", 0), testRemotePage("/X/Y/Z", "", 0), testRemotePage("/X/Y", "", 0)},
+			want:   []string{"create /A", "create /A/B", "create /A/B/C", "delete /X/Y/Z", "delete /X/Y", "delete /X"}, deleteMissing: true,
+		},
+		{
+			name:   "order file reorders sub-pages",
+			root:   "/",
+			local:  map[string]string{"/A": "a", "/B": "b"},
+			orders: map[string][]string{"/": {"B", "A"}},
+			remote: []wiki.WikiPage{testRemotePage("/A", "a", 0), testRemotePage("/B", "b", 1)},
+			want:   []string{"reorder /: /B, /A"}, unchanged: 2,
+		},
+		{
+			name:   "missing pages kept without delete_missing",
+			root:   "/",
+			local:  map[string]string{"/A": "a"},
+			orders: map[string][]string{"/": {"A"}},
+			remote: []wiki.WikiPage{testRemotePage("/A", "a", 0), testRemotePage("/Gone", "gone", 1)},
+			want:   nil, unchanged: 1,
+		},
+		{
+			name:   "full export deletes missing top-level pages",
+			root:   "/",
+			local:  map[string]string{"/A": "a"},
+			orders: map[string][]string{"/": {"A"}},
+			remote: []wiki.WikiPage{testRemotePage("/A", "a", 0), testRemotePage("/Gone", "gone", 1)},
+			want:   []string{"delete /Gone"}, unchanged: 1, deleteMissing: true,
+		},
+		{
+			name:  "subtree export leaves siblings alone",
+			root:  "/Parent",
+			local: map[string]string{"/Parent/Sub": "sub", "/Parent/Sub/Child": "child"},
+			remote: []wiki.WikiPage{
+				testRemotePage("/Parent", "parent", 0),
+				testRemotePage("/Parent/Sub", "sub", 0),
+				testRemotePage("/Parent/Sub/Child", "child", 0),
+				testRemotePage("/Parent/Sub/Old", "old", 1),
+				testRemotePage("/Parent/Other", "other", 1),
+			},
+			want: []string{"delete /Parent/Sub/Old"}, unchanged: 2, deleteMissing: true,
+		},
+		{
+			name:   "empty directory deletes nothing",
+			root:   "/",
+			remote: []wiki.WikiPage{testRemotePage("/A", "a", 0)},
+			want:   nil, deleteMissing: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, unchanged := planWikiImport(testLocalWiki(tt.local), tt.orders, tt.remote, tt.root, tt.deleteMissing)
+			if got := planSteps(plan); !reflect.DeepEqual(got, tt.want) || unchanged != tt.unchanged {
+				t.Errorf("planWikiImport = %q, %d unchanged, want %q, %d", got, unchanged, tt.want, tt.unchanged)
+			}
+		})
+	}
+}
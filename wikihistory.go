@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+)
+
+// Handler for listing the commits that changed a wiki page
+func handleGetWikiPageHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := normalizeWikiPath(request.Params.Arguments["path"].(string))
+	top := 20
+	if value, ok := request.Params.Arguments["top"].(float64); ok && value > 0 {
+		top = int(value)
+	}
+	skip := 0
+	if value, ok := request.Params.Arguments["skip"].(float64); ok && value > 0 {
+		skip = int(value)
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	gitPath, err := wikiPageGitPath(ctx, selectedWiki, path, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	commits, err := getWikiPageCommits(ctx, selectedWiki, gitPath, versionDescriptor, skip, top)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("History of wiki page %s\n%s\n\n", path, wikiRepositoryLocation(ctx, selectedWiki, versionDescriptor, gitPath)))
+	if len(commits) == 0 {
+		result.WriteString("No commits found.")
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	for _, commit := range commits {
+		result.WriteString(fmt.Sprintf("Commit: %s\n", stringOrEmpty(commit.CommitId)))
+		if commit.Author != nil {
+			author := stringOrEmpty(commit.Author.Name)
+			if commit.Author.Email != nil && *commit.Author.Email != "" {
+				author += fmt.Sprintf(" <%s>", *commit.Author.Email)
+			}
+			result.WriteString(fmt.Sprintf("Author: %s\n", author))
+			if commit.Author.Date != nil {
+				result.WriteString(fmt.Sprintf("Date: %s\n", commit.Author.Date.Time.Format(time.RFC3339)))
+			}
+		}
+		if commit.Comment != nil {
+			message := strings.TrimSpace(*commit.Comment)
+			if commit.CommentTruncated != nil && *commit.CommentTruncated {
+				message += "..."
+			}
+			result.WriteString(fmt.Sprintf("Message: %s\n", message))
+		}
+		result.WriteString("\n")
+	}
+	if len(commits) == top {
+		result.WriteString(fmt.Sprintf("More commits may exist, call again with skip=%d.", skip+top))
+	}
+
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// Handler for comparing two versions of a wiki page
+func handleDiffWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := normalizeWikiPath(request.Params.Arguments["path"].(string))
+	fromVersion, _ := request.Params.Arguments["from_version"].(string)
+	toVersion, _ := request.Params.Arguments["to_version"].(string)
+	contextLines := 3
+	if value, ok := request.Params.Arguments["context"].(float64); ok && value >= 0 {
+		contextLines = int(value)
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	gitPath, err := wikiPageGitPath(ctx, selectedWiki, path, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Without a starting version, show the most recent change
+	if fromVersion == "" {
+		commits, err := getWikiPageCommits(ctx, selectedWiki, gitPath, versionDescriptor, 0, 2)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(commits) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("No history found for wiki page %s", path)), nil
+		}
+		// A page with a single commit is compared against nothing, showing its creation
+		if len(commits) > 1 {
+			fromVersion = stringOrEmpty(commits[1].CommitId)
+		}
+		if toVersion == "" {
+			toVersion = stringOrEmpty(commits[0].CommitId)
+		}
+	}
+	if toVersion == "" {
+		toVersion = "current"
+	}
+
+	fromName, fromLines, err := readWikiPageVersion(ctx, selectedWiki, path, gitPath, fromVersion, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	toName, toLines, err := readWikiPageVersion(ctx, selectedWiki, path, gitPath, toVersion, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	diff := unifiedDiff(fromName, toName, fromLines, toLines, contextLines)
+	if diff == "" {
+		return mcp.NewToolResultText(fmt.Sprintf("No differences between %s and %s", fromName, toName)), nil
+	}
+	return mcp.NewToolResultText(diff), nil
+}
+
+// readWikiPageVersion reads a page at a commit, the current page when commit is "current",
+// or nothing when commit is empty. Returns the name to show for the version and its lines.
+func readWikiPageVersion(ctx context.Context, w *wiki.WikiV2, path, gitPath, commit string, versionDescriptor *git.GitVersionDescriptor) (string, []string, error) {
+	switch commit {
+	case "":
+		return "/dev/null", nil, nil
+	case "current":
+		state, err := getWikiPageState(ctx, w.Id.String(), path, versionDescriptor)
+		if err != nil {
+			return "", nil, err
+		}
+		return path + " (current)", splitLines(state.Content), nil
+	}
+
+	content, err := readGitItemAtCommit(ctx, w, gitPath, commit)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s @ %s", path, commit), splitLines(content), nil
+}
+
+// readGitItemAtCommit reads a file of the wiki repository as it was at a commit
+func readGitItemAtCommit(ctx context.Context, w *wiki.WikiV2, gitPath, commit string) (string, error) {
+	reader, err := readWikiRepositoryFile(ctx, w, gitPath, &git.GitVersionDescriptor{
+		Version:     &commit,
+		VersionType: &git.GitVersionTypeValues.Commit,
+	})
+	if err != nil {
+		if azureStatusCode(err) == http.StatusNotFound {
+			return "", fmt.Errorf("%s did not exist at version %s; the page may have been moved since", gitPath, commit)
+		}
+		return "", fmt.Errorf("failed to read %s at version %s: %v", gitPath, commit, err)
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s at version %s: %v", gitPath, commit, err)
+	}
+	return string(content), nil
+}
+
+// readWikiRepositoryFile opens a file of the repository backing a wiki
+func readWikiRepositoryFile(ctx context.Context, w *wiki.WikiV2, gitPath string, versionDescriptor *git.GitVersionDescriptor) (io.ReadCloser, error) {
+	if w.RepositoryId == nil {
+		return nil, fmt.Errorf("wiki %s has no backing repository", *w.Name)
+	}
+	repositoryID := w.RepositoryId.String()
+	return gitClient.GetItemContent(ctx, git.GetItemContentArgs{
+		RepositoryId:      &repositoryID,
+		Project:           &config.Project,
+		Path:              &gitPath,
+		VersionDescriptor: versionDescriptor,
+	})
+}
+
+// wikiPageGitPath returns the repository path of the markdown file behind a page
+func wikiPageGitPath(ctx context.Context, w *wiki.WikiV2, path string, versionDescriptor *git.GitVersionDescriptor) (string, error) {
+	state, err := getWikiPageState(ctx, w.Id.String(), path, versionDescriptor)
+	if err != nil {
+		return "", err
+	}
+	if !state.Exists {
+		return "", fmt.Errorf("wiki page %s not found", path)
+	}
+	gitPath := state.GitItemPath
+	if gitPath == "" {
+		gitPath = encodeWikiPath(path) + ".md"
+	}
+	// Code wiki pages live below the wiki's folder in the repository
+	if w.MappedPath != nil && *w.MappedPath != "/" && !isUnderWikiPath(gitPath, *w.MappedPath) {
+		gitPath = strings.TrimSuffix(*w.MappedPath, "/") + gitPath
+	}
+	return gitPath, nil
+}
+
+// getWikiPageCommits lists the commits that changed a page's file, newest first
+func getWikiPageCommits(ctx context.Context, w *wiki.WikiV2, gitPath string, versionDescriptor *git.GitVersionDescriptor, skip, top int) ([]git.GitCommitRef, error) {
+	if w.RepositoryId == nil {
+		return nil, fmt.Errorf("wiki %s has no backing repository", *w.Name)
+	}
+	repositoryID := w.RepositoryId.String()
+	commits, err := gitClient.GetCommits(ctx, git.GetCommitsArgs{
+		RepositoryId: &repositoryID,
+		Project:      &config.Project,
+		SearchCriteria: &git.GitQueryCommitsCriteria{
+			ItemPath:    &gitPath,
+			ItemVersion: versionDescriptor,
+			Skip:        &skip,
+			Top:         &top,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history of %s: %v", gitPath, err)
+	}
+	if commits == nil {
+		return nil, nil
+	}
+	return *commits, nil
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,509 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+)
+
+// BM25 parameters, and limits on chunk and passage sizes
+const (
+	bm25K1                 = 1.2
+	bm25B                  = 0.75
+	maxIndexChunkLength    = 1500
+	maxIndexPassageLength  = 800
+	wikiIndexFormatVersion = 1
+)
+
+// Matches markdown ATX headings such as "## Setup"
+var markdownHeadingPattern = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+
+// Loaded wiki indexes by file name. The mutex also serializes refreshes and searches.
+var (
+	wikiIndexMutex sync.Mutex
+	wikiIndexes    = map[string]*wikiIndex{}
+)
+
+// wikiIndex is the persisted search index of one version of a wiki
+type wikiIndex struct {
+	Format  int                       `json:"format"`
+	WikiID  string                    `json:"wikiId"`
+	Version string                    `json:"version"`
+	Updated time.Time                 `json:"updated"`
+	Pages   map[string]*wikiIndexPage `json:"pages"`
+
+	// Derived from Pages whenever they change
+	chunks        []wikiIndexChunkRef
+	postings      map[string][]wikiIndexPosting
+	averageLength float64
+}
+
+// wikiIndexPage is an indexed page, keyed by the git object ID of its markdown file
+type wikiIndexPage struct {
+	ObjectID    string           `json:"objectId"`
+	GitItemPath string           `json:"gitItemPath"`
+	Chunks      []wikiIndexChunk `json:"chunks"`
+}
+
+// wikiIndexChunk is a section of a page, or part of a long section
+type wikiIndexChunk struct {
+	Heading string `json:"heading"` // Heading path, such as "Setup > Linux"
+	Anchor  string `json:"anchor"`
+	Line    int    `json:"line"`
+	Text    string `json:"text"`
+}
+
+type wikiIndexChunkRef struct {
+	Page   string
+	Chunk  *wikiIndexChunk
+	Length int
+}
+
+type wikiIndexPosting struct {
+	Chunk int
+	Count int
+}
+
+// wikiIndexRefresh counts the pages changed by a refresh
+type wikiIndexRefresh struct {
+	Added, Updated, Removed int
+	Failed                  []string
+}
+
+// Handler for searching the local wiki index
+func handleSearchWikiIndex(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := request.Params.Arguments["query"].(string)
+	path, _ := request.Params.Arguments["path"].(string)
+	top := 5
+	if value, ok := request.Params.Arguments["top"].(float64); ok && value > 0 {
+		top = int(value)
+	}
+	refresh := true
+	if value, ok := request.Params.Arguments["refresh"].(bool); ok {
+		refresh = value
+	}
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return mcp.NewToolResultError("The query must contain at least one word"), nil
+	}
+	root := "/"
+	if path != "" {
+		root = normalizeWikiPath(path)
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	wikiIndexMutex.Lock()
+	defer wikiIndexMutex.Unlock()
+
+	index := loadWikiIndex(selectedWiki, versionDescriptor)
+
+	var status strings.Builder
+	if refresh || len(index.Pages) == 0 {
+		changes, err := refreshWikiIndex(ctx, index, selectedWiki, versionDescriptor)
+		switch {
+		case err != nil && len(index.Pages) == 0:
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to build wiki index: %v", err)), nil
+		case err != nil:
+			status.WriteString(fmt.Sprintf("Could not refresh the index (%v), searching the index from %s.\n",
+				err, index.Updated.Format(time.RFC3339)))
+		default:
+			status.WriteString(fmt.Sprintf("Index refreshed: %d page(s) added, %d updated, %d removed.\n",
+				changes.Added, changes.Updated, changes.Removed))
+			if len(changes.Failed) > 0 {
+				status.WriteString(fmt.Sprintf("Could not index %d page(s), they will be retried next time:\n- %s\n",
+					len(changes.Failed), strings.Join(changes.Failed, "\n- ")))
+			}
+		}
+		if err == nil && changes.Added+changes.Updated+changes.Removed > 0 {
+			if err := saveWikiIndex(index); err != nil {
+				status.WriteString(fmt.Sprintf("Could not save the index: %v\n", err))
+			}
+		}
+	} else {
+		status.WriteString(fmt.Sprintf("Searching the index from %s without refreshing.\n", index.Updated.Format(time.RFC3339)))
+	}
+
+	hits := index.search(terms, root, top)
+
+	var result strings.Builder
+	result.WriteString(status.String())
+	result.WriteString(fmt.Sprintf("Wiki %s: %d page(s), %d passage(s) indexed.\n", *selectedWiki.Name, len(index.Pages), len(index.chunks)))
+	if len(hits) == 0 {
+		result.WriteString(fmt.Sprintf("\nNo passages found for '%s'", query))
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	result.WriteString(fmt.Sprintf("\nTop %d passage(s) for '%s':\n", len(hits), query))
+	for i, hit := range hits {
+		ref := index.chunks[hit.Chunk]
+		title := ref.Page
+		if ref.Chunk.Heading != "" {
+			title += " > " + ref.Chunk.Heading
+		}
+		citation := ref.Page
+		if ref.Chunk.Anchor != "" {
+			citation += "#" + ref.Chunk.Anchor
+		}
+		citation += fmt.Sprintf(", line %d", ref.Chunk.Line)
+		if page := index.Pages[ref.Page]; page != nil && page.GitItemPath != "" {
+			citation += fmt.Sprintf(" of %s", page.GitItemPath)
+		}
+
+		result.WriteString(fmt.Sprintf("\n%d. %s (score %.2f)\n", i+1, title, hit.Score))
+		result.WriteString(fmt.Sprintf("Source: %s\n", citation))
+		result.WriteString(truncatePassage(ref.Chunk.Text) + "\n")
+	}
+
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// wikiIndexFile returns the file an index is stored in, under AZURE_DEVOPS_INDEX_DIR or the user cache directory
+func wikiIndexFile(wikiID, version string) (string, error) {
+	directory := config.IndexDirectory
+	if directory == "" {
+		cacheDirectory, err := os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("no index directory configured: %v", err)
+		}
+		directory = filepath.Join(cacheDirectory, "mcp-azuredevops-bridge", "wiki-index")
+	}
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' {
+			return r
+		}
+		return '_'
+	}, wikiID+"-"+version)
+	return filepath.Join(directory, name+".json"), nil
+}
+
+// wikiIndexVersion names the indexed version of a wiki
+func wikiIndexVersion(versionDescriptor *git.GitVersionDescriptor) string {
+	if versionDescriptor == nil || versionDescriptor.Version == nil {
+		return "default"
+	}
+	versionType := "branch"
+	if versionDescriptor.VersionType != nil {
+		versionType = string(*versionDescriptor.VersionType)
+	}
+	return versionType + "-" + *versionDescriptor.Version
+}
+
+// loadWikiIndex returns the index of a wiki version from memory or disk, or an empty index
+func loadWikiIndex(w *wiki.WikiV2, versionDescriptor *git.GitVersionDescriptor) *wikiIndex {
+	wikiID := w.Id.String()
+	version := wikiIndexVersion(versionDescriptor)
+	fileName, err := wikiIndexFile(wikiID, version)
+	if err != nil {
+		fileName = wikiID + "-" + version
+	}
+	if index, ok := wikiIndexes[fileName]; ok {
+		return index
+	}
+
+	index := &wikiIndex{}
+	if data, err := os.ReadFile(fileName); err == nil {
+		if err := json.Unmarshal(data, index); err != nil || index.Format != wikiIndexFormatVersion {
+			index = &wikiIndex{}
+		}
+	}
+	index.Format = wikiIndexFormatVersion
+	index.WikiID = wikiID
+	index.Version = version
+	if index.Pages == nil {
+		index.Pages = map[string]*wikiIndexPage{}
+	}
+	index.rebuild()
+	wikiIndexes[fileName] = index
+	return index
+}
+
+// saveWikiIndex writes an index to disk, replacing the previous file atomically
+func saveWikiIndex(index *wikiIndex) error {
+	fileName, err := wikiIndexFile(index.WikiID, index.Version)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	temporary := fileName + ".tmp"
+	if err := os.WriteFile(temporary, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(temporary, fileName)
+}
+
+// refreshWikiIndex lists the markdown files backing the wiki and re-indexes only those whose
+// git object ID changed since the last refresh
+func refreshWikiIndex(ctx context.Context, index *wikiIndex, w *wiki.WikiV2, versionDescriptor *git.GitVersionDescriptor) (wikiIndexRefresh, error) {
+	var changes wikiIndexRefresh
+	if w.RepositoryId == nil {
+		return changes, fmt.Errorf("wiki %s has no backing repository", *w.Name)
+	}
+	repositoryID := w.RepositoryId.String()
+
+	scopePath := "/"
+	if w.MappedPath != nil && *w.MappedPath != "" {
+		scopePath = *w.MappedPath
+	}
+	recursionLevel := git.VersionControlRecursionTypeValues.Full
+	items, err := gitClient.GetItems(ctx, git.GetItemsArgs{
+		RepositoryId:      &repositoryID,
+		Project:           &config.Project,
+		ScopePath:         &scopePath,
+		RecursionLevel:    &recursionLevel,
+		VersionDescriptor: versionDescriptor,
+	})
+	if err != nil {
+		return changes, fmt.Errorf("failed to list wiki files: %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, item := range *items {
+		if item.Path == nil || item.ObjectId == nil || (item.IsFolder != nil && *item.IsFolder) {
+			continue
+		}
+		if !strings.HasSuffix(strings.ToLower(*item.Path), ".md") || strings.Contains(*item.Path, "/.attachments/") {
+			continue
+		}
+		pagePath := wikiPagePathFromGitPath(w, *item.Path)
+		seen[pagePath] = true
+
+		existing := index.Pages[pagePath]
+		if existing != nil && existing.ObjectID == *item.ObjectId {
+			continue
+		}
+
+		content, err := readGitBlob(ctx, repositoryID, *item.ObjectId)
+		if err != nil {
+			changes.Failed = append(changes.Failed, fmt.Sprintf("%s: %v", pagePath, err))
+			continue
+		}
+		index.Pages[pagePath] = &wikiIndexPage{
+			ObjectID:    *item.ObjectId,
+			GitItemPath: *item.Path,
+			Chunks:      chunkMarkdown(content),
+		}
+		if existing == nil {
+			changes.Added++
+		} else {
+			changes.Updated++
+		}
+	}
+
+	for pagePath := range index.Pages {
+		if !seen[pagePath] {
+			delete(index.Pages, pagePath)
+			changes.Removed++
+		}
+	}
+
+	index.Updated = time.Now().UTC()
+	if changes.Added+changes.Updated+changes.Removed > 0 {
+		index.rebuild()
+	}
+	return changes, nil
+}
+
+// readGitBlob reads a file's content by its git object ID
+func readGitBlob(ctx context.Context, repositoryID, objectID string) (string, error) {
+	reader, err := gitClient.GetBlobContent(ctx, git.GetBlobContentArgs{
+		RepositoryId: &repositoryID,
+		Sha1:         &objectID,
+		Project:      &config.Project,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// rebuild recomputes the inverted index from the indexed pages
+func (index *wikiIndex) rebuild() {
+	index.chunks = nil
+	index.postings = map[string][]wikiIndexPosting{}
+
+	pagePaths := make([]string, 0, len(index.Pages))
+	for pagePath := range index.Pages {
+		pagePaths = append(pagePaths, pagePath)
+	}
+	sort.Strings(pagePaths)
+
+	totalLength := 0
+	for _, pagePath := range pagePaths {
+		page := index.Pages[pagePath]
+		for i := range page.Chunks {
+			chunk := &page.Chunks[i]
+			// Parent headings count towards the chunk so a passage matches its section's topic
+			tokens := append(tokenizeText(chunk.Text), tokenizeText(chunk.Heading)...)
+			counts := map[string]int{}
+			for _, token := range tokens {
+				counts[token]++
+			}
+			chunkIndex := len(index.chunks)
+			for token, count := range counts {
+				index.postings[token] = append(index.postings[token], wikiIndexPosting{Chunk: chunkIndex, Count: count})
+			}
+			index.chunks = append(index.chunks, wikiIndexChunkRef{Page: pagePath, Chunk: chunk, Length: len(tokens)})
+			totalLength += len(tokens)
+		}
+	}
+	index.averageLength = 0
+	if len(index.chunks) > 0 {
+		index.averageLength = float64(totalLength) / float64(len(index.chunks))
+	}
+}
+
+type wikiIndexHit struct {
+	Chunk int
+	Score float64
+}
+
+// search ranks the chunks of pages under root against the terms with BM25
+func (index *wikiIndex) search(terms []string, root string, top int) []wikiIndexHit {
+	total := float64(len(index.chunks))
+	scores := map[int]float64{}
+	for _, term := range terms {
+		postings := index.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		documents := float64(len(postings))
+		idf := math.Log(1 + (total-documents+0.5)/(documents+0.5))
+		for _, posting := range postings {
+			ref := index.chunks[posting.Chunk]
+			if !isUnderWikiPath(ref.Page, root) {
+				continue
+			}
+			count := float64(posting.Count)
+			norm := 1 - bm25B + bm25B*float64(ref.Length)/index.averageLength
+			scores[posting.Chunk] += idf * count * (bm25K1 + 1) / (count + bm25K1*norm)
+		}
+	}
+
+	hits := make([]wikiIndexHit, 0, len(scores))
+	for chunk, score := range scores {
+		hits = append(hits, wikiIndexHit{Chunk: chunk, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Chunk < hits[j].Chunk
+	})
+	if len(hits) > top {
+		hits = hits[:top]
+	}
+	return hits
+}
+
+// chunkMarkdown splits a page into its sections, and long sections at paragraph breaks
+func chunkMarkdown(content string) []wikiIndexChunk {
+	var chunks []wikiIndexChunk
+	var headings []string
+	var levels []int
+	var lines []string
+	start, length := 1, 0
+	inFence := false
+
+	flush := func(next int) {
+		text := strings.TrimSpace(strings.Join(lines, "\n"))
+		if text != "" {
+			chunk := wikiIndexChunk{Heading: strings.Join(headings, " > "), Line: start, Text: text}
+			if len(headings) > 0 {
+				chunk.Anchor = wikiHeadingAnchor(headings[len(headings)-1])
+			}
+			chunks = append(chunks, chunk)
+		}
+		lines, start, length = nil, next, 0
+	}
+
+	for i, line := range splitLines(content) {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if !inFence {
+			if m := markdownHeadingPattern.FindStringSubmatch(line); m != nil {
+				flush(i + 1)
+				level := len(m[1])
+				for len(levels) > 0 && levels[len(levels)-1] >= level {
+					levels = levels[:len(levels)-1]
+					headings = headings[:len(headings)-1]
+				}
+				levels = append(levels, level)
+				headings = append(headings, m[2])
+			} else if trimmed == "" && length > maxIndexChunkLength {
+				flush(i + 2)
+				continue
+			}
+		}
+		if len(lines) == 0 && trimmed == "" {
+			start = i + 2
+			continue
+		}
+		lines = append(lines, line)
+		length += len(line) + 1
+	}
+	flush(0)
+	return chunks
+}
+
+// wikiHeadingAnchor returns the anchor the wiki generates for a heading
+func wikiHeadingAnchor(heading string) string {
+	var anchor strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			anchor.WriteRune(r)
+		case r == ' ':
+			anchor.WriteRune('-')
+		}
+	}
+	return anchor.String()
+}
+
+// truncatePassage shortens a passage for display, cutting at a line break when possible
+func truncatePassage(text string) string {
+	runes := []rune(text)
+	if len(runes) <= maxIndexPassageLength {
+		return text
+	}
+	cut := string(runes[:maxIndexPassageLength])
+	if i := strings.LastIndex(cut, "\n"); i > maxIndexPassageLength/2 {
+		cut = cut[:i]
+	}
+	return cut + "\n..."
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testWikiIndex builds an index from page contents keyed by path
+func testWikiIndex(pages map[string]string) *wikiIndex {
+	index := &wikiIndex{Pages: map[string]*wikiIndexPage{}}
+	for pagePath, content := range pages {
+		index.Pages[pagePath] = &wikiIndexPage{Chunks: chunkMarkdown(content)}
+	}
+	index.rebuild()
+	return index
+}
+
+// hitPages returns the page and heading of each hit
+func hitPages(index *wikiIndex, hits []wikiIndexHit) []string {
+	var pages []string
+	for _, hit := range hits {
+		ref := index.chunks[hit.Chunk]
+		pages = append(pages, ref.Page+"#"+ref.Chunk.Anchor)
+	}
+	return pages
+}
+
+func TestWikiIndexSearch(t *testing.T) {
+	index := testWikiIndex(map[string]string{
+		"/Team/Deploy":  "# Deploy\nRun the deploy pipeline.\n\n## Rollback\nRedeploy the previous release.",
+		"/Team/Onboard": "# Onboarding\nInstall the tools. Ask for access to the deploy pipeline.",
+		"/Other/Notes":  "# Notes\nDeploy deploy deploy notes from the retro.",
+		"/Other/Misc":   "# Misc\nNothing about releases here, just lunch plans.",
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		root  string
+		top   int
+		want  []string
+	}{
+		{"shorter chunks rank first at equal frequency", "deploy", "/", 10, []string{"/Team/Deploy#deploy", "/Other/Notes#notes", "/Team/Deploy#rollback", "/Team/Onboard#onboarding"}},
+		{"root limits pages", "deploy", "/Team", 10, []string{"/Team/Deploy#deploy", "/Team/Deploy#rollback", "/Team/Onboard#onboarding"}},
+		{"heading terms match the section", "rollback", "/", 10, []string{"/Team/Deploy#rollback"}},
+		{"rare terms outweigh common ones", "access deploy", "/", 1, []string{"/Team/Onboard#onboarding"}},
+		{"top limits hits", "deploy", "/", 2, []string{"/Team/Deploy#deploy", "/Other/Notes#notes"}},
+		{"unknown terms", "kubernetes", "/", 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hitPages(index, index.search(searchTerms(tt.query), tt.root, tt.top))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("search(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWikiIndexRebuild(t *testing.T) {
+	index := testWikiIndex(map[string]string{
+		"/A": "# Title\nalpha beta beta",
+		"/B": "gamma",
+	})
+
+	if len(index.chunks) != 2 {
+		t.Fatalf("got %d chunks, want 2", len(index.chunks))
+	}
+	// The chunk of /A counts its text, which starts with the heading line, and its heading path
+	if ref := index.chunks[0]; ref.Page != "/A" || ref.Length != 5 {
+		t.Errorf("first chunk = %s with %d tokens, want /A with 5", ref.Page, ref.Length)
+	}
+	if want := []wikiIndexPosting{{Chunk: 0, Count: 2}}; !reflect.DeepEqual(index.postings["beta"], want) {
+		t.Errorf("postings for beta = %v, want %v", index.postings["beta"], want)
+	}
+	if index.averageLength != 3 {
+		t.Errorf("average length = %v, want 3", index.averageLength)
+	}
+}
+
+func TestChunkMarkdown(t *testing.T) {
+	content := strings.Join([]string{
+		"Intro text",
+		"# Setup",
+		"Install it.",
+		"```",
+		"# not a heading",
+		"```",
+		"## Linux",
+		"Use apt.",
+	}, "\n")
+
+	var got []string
+	for _, chunk := range chunkMarkdown(content) {
+		got = append(got, chunk.Heading+"|"+chunk.Anchor)
+	}
+	want := []string{"|", "Setup|setup", "Setup > Linux|linux"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("chunkMarkdown headings = %q, want %q", got, want)
+	}
+}
+
+func TestTruncatePassage(t *testing.T) {
+	short := "short passage"
+	if got := truncatePassage(short); got != short {
+		t.Errorf("truncatePassage(%q) = %q", short, got)
+	}
+
+	long := strings.Repeat("é", maxIndexPassageLength+10)
+	got := truncatePassage(long)
+	if !strings.HasSuffix(got, "\n...") || len([]rune(strings.TrimSuffix(got, "\n..."))) != maxIndexPassageLength {
+		t.Errorf("truncatePassage should cut long passages at %d runes", maxIndexPassageLength)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Most problems listed per section of the report
+const maxReportedLinkProblems = 200
+
+// Matches work item mentions such as #123, but not anchors inside link targets
+var workItemMentionPattern = regexp.MustCompile(`(?:^|[\s(\[,;:])#(\d+)\b`)
+
+// Matches inline code spans, which may contain anything that looks like a mention
+var inlineCodePattern = regexp.MustCompile("`[^`]*`")
+
+// wikiLinkProblem is a broken link found on a page
+type wikiLinkProblem struct {
+	Page    string
+	Line    int
+	Target  string
+	Problem string
+}
+
+// wikiPageAnchors returns the anchors of a page's headings
+func wikiPageAnchors(content string) map[string]bool {
+	anchors := map[string]bool{}
+	for _, section := range parseMarkdownSections(splitLines(content)) {
+		anchors[section.Anchor] = true
+	}
+	return anchors
+}
+
+// workItemMentions returns the work item IDs mentioned on each line outside code
+func workItemMentions(content string) map[int][]int {
+	mentions := map[int][]int{}
+	inFence := false
+	for i, line := range splitLines(content) {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		line = inlineCodePattern.ReplaceAllString(line, "")
+		for _, m := range workItemMentionPattern.FindAllStringSubmatch(line, -1) {
+			if id, err := strconv.Atoi(m[1]); err == nil {
+				mentions[id] = append(mentions[id], i+1)
+			}
+		}
+	}
+	return mentions
+}
+
+// Handler for checking the links of wiki pages
+func handleCheckWikiLinks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rootPath, _ := request.Params.Arguments["path"].(string)
+	checkWorkItems := true
+	if value, ok := request.Params.Arguments["check_work_items"].(bool); ok {
+		checkWorkItems = value
+	}
+	includeOrphans := true
+	if value, ok := request.Params.Arguments["include_orphans"].(bool); ok {
+		includeOrphans = value
+	}
+
+	root := "/"
+	if rootPath != "" {
+		root = normalizeWikiPath(rootPath)
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Links are checked on pages under root, but may point anywhere in the wiki
+	pages, err := getWikiPagesWithContent(ctx, selectedWiki.Id.String(), "/", versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	attachmentItems, err := getWikiAttachmentItems(ctx, selectedWiki, versionDescriptor, false)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	attachments := map[string]bool{}
+	for _, item := range attachmentItems {
+		attachments[strings.ToLower(path.Base(*item.Path))] = true
+	}
+
+	// Page paths are matched case-insensitively. The root page always exists, so links to / resolve.
+	contents := map[string]string{"/": ""}
+	byName := map[string][]string{}
+	for _, page := range pages {
+		if page.Path == nil {
+			continue
+		}
+		content := ""
+		if page.Content != nil {
+			content = *page.Content
+		}
+		contents[strings.ToLower(*page.Path)] = content
+		if *page.Path == "/" {
+			continue
+		}
+		name := strings.ToLower(path.Base(*page.Path))
+		byName[name] = append(byName[name], *page.Path)
+	}
+	anchorCache := map[string]map[string]bool{}
+	anchorsOf := func(pagePath string) map[string]bool {
+		key := strings.ToLower(pagePath)
+		if anchors, ok := anchorCache[key]; ok {
+			return anchors
+		}
+		anchors := wikiPageAnchors(contents[key])
+		anchorCache[key] = anchors
+		return anchors
+	}
+	checkAnchor := func(pagePath, anchor string) bool {
+		anchor = strings.ToLower(strings.TrimPrefix(anchor, "#"))
+		return anchor == "" || anchorsOf(pagePath)[anchor]
+	}
+
+	var problems []wikiLinkProblem
+	inbound := map[string]int{}
+	mentions := map[int][]wikiLinkProblem{}
+	checkedPages, checkedLinks := 0, 0
+	for _, page := range pages {
+		if page.Path == nil {
+			continue
+		}
+		source := *page.Path
+		content := contents[strings.ToLower(source)]
+		inScope := isUnderWikiPath(source, root)
+		if inScope {
+			checkedPages++
+		}
+
+		scanMarkdownLinks(content, func(line int, target string) (string, bool) {
+			if urlSchemePattern.MatchString(target) {
+				return "", false
+			}
+			report := func(problem string) {
+				if inScope {
+					problems = append(problems, wikiLinkProblem{Page: source, Line: line, Target: target, Problem: problem})
+				}
+			}
+			if inScope {
+				checkedLinks++
+			}
+
+			switch {
+			case strings.Contains(target, ".attachments/"):
+				if name := wikiAttachmentName(target); !attachments[strings.ToLower(name)] {
+					report(fmt.Sprintf("attachment %s not found", name))
+				}
+			case strings.HasPrefix(target, "#"):
+				if !checkAnchor(source, target) {
+					report(fmt.Sprintf("heading %s not found on this page", target))
+				}
+			default:
+				link, ok := parseWikiLink(target, source)
+				if !ok {
+					return "", false
+				}
+				key := strings.ToLower(link.Page)
+				if _, exists := contents[key]; !exists {
+					problem := fmt.Sprintf("page %s not found", link.Page)
+					if candidates := byName[strings.ToLower(path.Base(link.Page))]; len(candidates) > 0 {
+						problem += fmt.Sprintf(" (did you mean %s?)", strings.Join(candidates, " or "))
+					}
+					report(problem)
+					return "", false
+				}
+				if key != strings.ToLower(source) {
+					inbound[key]++
+				}
+				if !checkAnchor(link.Page, link.Anchor) {
+					report(fmt.Sprintf("heading %s not found on %s", link.Anchor, link.Page))
+				}
+			}
+			return "", false
+		})
+
+		if inScope && checkWorkItems {
+			for id, lines := range workItemMentions(content) {
+				for _, line := range lines {
+					mentions[id] = append(mentions[id], wikiLinkProblem{Page: source, Line: line,
+						Target: fmt.Sprintf("#%d", id), Problem: "work item not found"})
+				}
+			}
+		}
+	}
+
+	if len(mentions) > 0 {
+		ids := make([]int, 0, len(mentions))
+		for id := range mentions {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		found, err := getWorkItemsAsOf(ctx, ids, []string{"System.Id"}, nil)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to check work item mentions: %v", err)), nil
+		}
+		for _, id := range ids {
+			if _, ok := found[id]; ok {
+				continue
+			}
+			problems = append(problems, mentions[id]...)
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Page != problems[j].Page {
+			return problems[i].Page < problems[j].Page
+		}
+		return problems[i].Line < problems[j].Line
+	})
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Checked %d page(s) and %d link(s) in wiki %s", checkedPages, checkedLinks, *selectedWiki.Name))
+	if root != "/" {
+		result.WriteString(" under " + root)
+	}
+	if checkWorkItems {
+		result.WriteString(fmt.Sprintf(", with %d work item mention(s)", len(mentions)))
+	}
+	result.WriteString(".\n")
+
+	if len(problems) == 0 {
+		result.WriteString("\nNo broken links found.\n")
+	} else {
+		result.WriteString(fmt.Sprintf("\nBroken links (%d):\n", len(problems)))
+		for i, problem := range problems {
+			if i == maxReportedLinkProblems {
+				result.WriteString(fmt.Sprintf("... and %d more\n", len(problems)-i))
+				break
+			}
+			result.WriteString(fmt.Sprintf("- %s line %d: %s - %s\n", problem.Page, problem.Line, problem.Target, problem.Problem))
+		}
+	}
+
+	if includeOrphans {
+		var orphans []string
+		for _, page := range pages {
+			if page.Path == nil || *page.Path == "/" || !isUnderWikiPath(*page.Path, root) {
+				continue
+			}
+			if inbound[strings.ToLower(*page.Path)] == 0 {
+				orphans = append(orphans, *page.Path)
+			}
+		}
+		sort.Strings(orphans)
+		if len(orphans) == 0 {
+			result.WriteString("\nEvery page is linked from at least one other page.\n")
+		} else {
+			result.WriteString(fmt.Sprintf("\nOrphan pages not linked from any other page (%d):\n", len(orphans)))
+			for i, orphan := range orphans {
+				if i == maxReportedLinkProblems {
+					result.WriteString(fmt.Sprintf("... and %d more\n", len(orphans)-i))
+					break
+				}
+				result.WriteString("- " + orphan + "\n")
+			}
+		}
+	}
+
+	return mcp.NewToolResultText(result.String()), nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Matches inline markdown links and images: [text](target "title"), with the wiki's optional image size (target =300x200)
+var markdownLinkPattern = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?(?:\s+=\d*x\d*)?\s*\)`)
+
+// Matches reference-style link definitions: [id]: target
+var markdownReferencePattern = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s*<?([^\s>]+)>?`)
+
+// Matches URLs with a scheme such as https: or mailto:
+var urlSchemePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.\-]*:`)
+
+// wikiLink is a link to a wiki page, with the details needed to write it back in the same style
+type wikiLink struct {
+	Page     string // Absolute path of the page the link points at
+	Anchor   string // Fragment including the leading '#', if any
+	Relative bool
+	MDSuffix bool
+}
+
+// scanMarkdownLinks calls visit for the target of every link outside code blocks. When visit
+// returns ok, the target is replaced with the returned one. Returns the content and the number of
+// replaced targets.
+func scanMarkdownLinks(content string, visit func(line int, target string) (string, bool)) (string, int) {
+	lines := strings.Split(content, "\n")
+	replaced := 0
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		replace := func(match, target string) string {
+			newTarget, ok := visit(i+1, target)
+			if !ok || newTarget == target {
+				return match
+			}
+			replaced++
+			// The target follows the link text, so search after it to avoid touching the text
+			start := strings.LastIndex(match, "](") + 1
+			if start == 0 {
+				start = strings.Index(match, ":")
+			}
+			return match[:start] + strings.Replace(match[start:], target, newTarget, 1)
+		}
+
+		line = markdownLinkPattern.ReplaceAllStringFunc(line, func(match string) string {
+			return replace(match, markdownLinkPattern.FindStringSubmatch(match)[1])
+		})
+		if m := markdownReferencePattern.FindStringSubmatch(line); m != nil {
+			line = strings.Replace(line, m[0], replace(m[0], m[1]), 1)
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n"), replaced
+}
+
+// parseWikiLink resolves a link target found on sourcePage to a wiki page. It returns false for
+// external links, anchors on the same page and attachments.
+func parseWikiLink(target, sourcePage string) (wikiLink, bool) {
+	if target == "" || strings.HasPrefix(target, "#") || urlSchemePattern.MatchString(target) {
+		return wikiLink{}, false
+	}
+
+	var link wikiLink
+	if i := strings.Index(target, "#"); i != -1 {
+		link.Anchor = target[i:]
+		target = target[:i]
+	}
+	if i := strings.Index(target, "?"); i != -1 {
+		target = target[:i]
+	}
+	if target == "" || strings.Contains(target, ".attachments/") {
+		return wikiLink{}, false
+	}
+	if strings.HasSuffix(strings.ToLower(target), ".md") {
+		link.MDSuffix = true
+		target = target[:len(target)-3]
+	}
+
+	// Wiki links write spaces as hyphens and literal hyphens as %2D
+	decoded := strings.ReplaceAll(target, "-", " ")
+	if unescaped, err := url.PathUnescape(decoded); err == nil {
+		decoded = unescaped
+	}
+
+	if !strings.HasPrefix(decoded, "/") {
+		link.Relative = true
+		decoded = path.Join(path.Dir(sourcePage), decoded)
+	}
+	link.Page = normalizeWikiPath(path.Clean(decoded))
+	return link, true
+}
+
+// encodeWikiPath escapes a page path the way the wiki writes links
+func encodeWikiPath(pagePath string) string {
+	segments := strings.Split(pagePath, "/")
+	for i, segment := range segments {
+		if segment == "." || segment == ".." {
+			continue
+		}
+		escaped := url.PathEscape(segment)
+		escaped = strings.ReplaceAll(escaped, "-", "%2D")
+		segments[i] = strings.ReplaceAll(escaped, "%20", "-")
+	}
+	return strings.Join(segments, "/")
+}
+
+// formatWikiLink writes a link to page from sourcePage in the style of an existing link
+func formatWikiLink(link wikiLink, page, sourcePage string) string {
+	target := page
+	if link.Relative {
+		target = relativeWikiPath(path.Dir(sourcePage), page)
+	}
+	target = encodeWikiPath(target)
+	if link.MDSuffix {
+		target += ".md"
+	}
+	return target + link.Anchor
+}
+
+// relativeWikiPath returns the path of page relative to the page folder dir
+func relativeWikiPath(dir, page string) string {
+	split := func(p string) []string {
+		if p = strings.Trim(path.Clean(p), "/"); p == "" || p == "." {
+			return nil
+		}
+		return strings.Split(p, "/")
+	}
+	from, to := split(dir), split(page)
+	common := 0
+	for common < len(from) && common < len(to) && from[common] == to[common] {
+		common++
+	}
+
+	var segments []string
+	for range from[common:] {
+		segments = append(segments, "..")
+	}
+	segments = append(segments, to[common:]...)
+	if len(segments) == 0 {
+		return "."
+	}
+	return strings.Join(segments, "/")
+}
+
+// isUnderWikiPath reports whether page is root or one of its sub-pages
+func isUnderWikiPath(page, root string) bool {
+	if root == "/" {
+		return strings.HasPrefix(page, "/")
+	}
+	return strings.EqualFold(page, root) || strings.HasPrefix(strings.ToLower(page), strings.ToLower(root)+"/")
+}
+
+// rebaseWikiPath moves a page path from under oldRoot to under newRoot
+func rebaseWikiPath(page, oldRoot, newRoot string) string {
+	if !isUnderWikiPath(page, oldRoot) {
+		return page
+	}
+	return newRoot + page[len(oldRoot):]
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseWikiLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		source string
+		want   wikiLink
+		ok     bool
+	}{
+		{"absolute", "/Team/Getting-Started", "/Home", wikiLink{Page: "/Team/Getting Started"}, true},
+		{"escaped hyphen", "/Team/Follow%2Dup", "/Home", wikiLink{Page: "/Team/Follow-up"}, true},
+		{"relative", "Setup", "/Team/Guide", wikiLink{Page: "/Team/Setup", Relative: true}, true},
+		{"parent", "../Other", "/Team/Guide/Intro", wikiLink{Page: "/Team/Other", Relative: true}, true},
+		{"anchor", "/Team#install-steps", "/Home", wikiLink{Page: "/Team", Anchor: "#install-steps"}, true},
+		{"md suffix", "/Team/Guide.md", "/Home", wikiLink{Page: "/Team/Guide", MDSuffix: true}, true},
+		{"query", "/Team?anchor=x", "/Home", wikiLink{Page: "/Team"}, true},
+		{"root", "/", "/Home", wikiLink{Page: "/"}, true},
+		{"external", "https://example.com/page", "/Home", wikiLink{}, false},
+		{"mailto", "mailto:someone@example.com", "/Home", wikiLink{}, false},
+		{"same page anchor", "#section", "/Home", wikiLink{}, false},
+		{"attachment", "/.attachments/image.png", "/Home", wikiLink{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseWikiLink(tt.target, tt.source)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseWikiLink(%q, %q) = %+v, %v, want %+v, %v", tt.target, tt.source, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestEncodeWikiPath(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"/Team/Getting Started", "/Team/Getting-Started"},
+		{"/Team/Follow-up", "/Team/Follow%2Dup"},
+		{"../Other Page", "../Other-Page"},
+		{"/Q&A", "/Q&A"},
+		{"/100% done", "/100%25-done"},
+	}
+	for _, tt := range tests {
+		if got := encodeWikiPath(tt.input); got != tt.want {
+			t.Errorf("encodeWikiPath(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeWikiPathRoundTrip(t *testing.T) {
+	for _, page := range []string{"/Team/Getting Started", "/Team/Follow-up", "/A - B/C"} {
+		link, ok := parseWikiLink(encodeWikiPath(page), "/")
+		if !ok || link.Page != page {
+			t.Errorf("parseWikiLink(encodeWikiPath(%q)) = %q, want the original path", page, link.Page)
+		}
+	}
+}
+
+func TestFormatWikiLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		link   wikiLink
+		page   string
+		source string
+		want   string
+	}{
+		{"absolute", wikiLink{Anchor: "#intro"}, "/New Place/Page", "/Home", "/New-Place/Page#intro"},
+		{"relative", wikiLink{Relative: true}, "/Team/Other Page", "/Team/Guide", "Other-Page"},
+		{"relative parent", wikiLink{Relative: true}, "/Archive/Page", "/Team/Guide", "../Archive/Page"},
+		{"md suffix", wikiLink{MDSuffix: true}, "/Team/Page", "/Home", "/Team/Page.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatWikiLink(tt.link, tt.page, tt.source); got != tt.want {
+				t.Errorf("formatWikiLink = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelativeWikiPath(t *testing.T) {
+	tests := []struct {
+		dir, page string
+		want      string
+	}{
+		{"/Team", "/Team/Guide", "Guide"},
+		{"/Team", "/Team/Guide/Linux", "Guide/Linux"},
+		{"/Team/Guide", "/Team/Other", "../Other"},
+		{"/Team/Guide", "/Archive/Page", "../../Archive/Page"},
+		{"/", "/Team/Guide", "Team/Guide"},
+		{"/Team", "/", ".."},
+		{"/Team", "/Team", "."},
+	}
+	for _, tt := range tests {
+		if got := relativeWikiPath(tt.dir, tt.page); got != tt.want {
+			t.Errorf("relativeWikiPath(%q, %q) = %q, want %q", tt.dir, tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestScanMarkdownLinks(t *testing.T) {
+	content := strings.Join([]string{
+		"See [the guide](/Team/Guide) and ![diagram](/.attachments/a.png =300x).",
+		"```",
+		"[not a link](/Team/Guide)",
+		"```",
+		"[guide]: /Team/Guide",
+	}, "\n")
+
+	var targets []string
+	updated, replaced := scanMarkdownLinks(content, func(line int, target string) (string, bool) {
+		targets = append(targets, target)
+		if target == "/Team/Guide" {
+			return "/Team/Handbook", true
+		}
+		return "", false
+	})
+
+	if want := "/Team/Guide /.attachments/a.png /Team/Guide"; strings.Join(targets, " ") != want {
+		t.Errorf("visited targets %q, want %q", strings.Join(targets, " "), want)
+	}
+	if replaced != 2 {
+		t.Errorf("replaced %d targets, want 2", replaced)
+	}
+	want := strings.Join([]string{
+		"See [the guide](/Team/Handbook) and ![diagram](/.attachments/a.png =300x).",
+		"```",
+		"[not a link](/Team/Guide)",
+		"```",
+		"[guide]: /Team/Handbook",
+	}, "\n")
+	if updated != want {
+		t.Errorf("updated content =\n%s\nwant\n%s", updated, want)
+	}
+}
+
+func TestRebaseWikiPath(t *testing.T) {
+	tests := []struct {
+		page, oldRoot, newRoot string
+		want                   string
+	}{
+		{"/Team/Guide", "/Team", "/Archive/Team", "/Archive/Team/Guide"},
+		{"/Team", "/Team", "/Archive/Team", "/Archive/Team"},
+		{"/Teams/Guide", "/Team", "/Archive/Team", "/Teams/Guide"},
+		{"/team/guide", "/Team", "/Archive", "/Archive/guide"},
+	}
+	for _, tt := range tests {
+		if got := rebaseWikiPath(tt.page, tt.oldRoot, tt.newRoot); got != tt.want {
+			t.Errorf("rebaseWikiPath(%q, %q, %q) = %q, want %q", tt.page, tt.oldRoot, tt.newRoot, got, tt.want)
+		}
+	}
+}
+
+func TestIsUnderWikiPath(t *testing.T) {
+	tests := []struct {
+		page, root string
+		want       bool
+	}{
+		{"/Team", "/Team", true},
+		{"/Team/Guide", "/Team", true},
+		{"/team/guide", "/Team", true},
+		{"/Teams", "/Team", false},
+		{"/Other", "/Team", false},
+		{"/", "/", true},
+		{"/Team/Guide", "/", true},
+	}
+	for _, tt := range tests {
+		if got := isUnderWikiPath(tt.page, tt.root); got != tt.want {
+			t.Errorf("isUnderWikiPath(%q, %q) = %v, want %v", tt.page, tt.root, got, tt.want)
+		}
+	}
+}
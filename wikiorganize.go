@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+)
+
+// Handler for moving, renaming or reordering a wiki page
+func handleMoveWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := normalizeWikiPath(request.Params.Arguments["path"].(string))
+	newPath, _ := request.Params.Arguments["new_path"].(string)
+	order, hasOrder := request.Params.Arguments["order"].(float64)
+	comment, _ := request.Params.Arguments["comment"].(string)
+	updateLinks := true
+	if value, ok := request.Params.Arguments["update_links"].(bool); ok {
+		updateLinks = value
+	}
+
+	if newPath == "" && !hasOrder {
+		return mcp.NewToolResultError("Either new_path or order is required"), nil
+	}
+	if newPath == "" {
+		newPath = path
+	}
+	newPath = normalizeWikiPath(newPath)
+	if path == "/" {
+		return mcp.NewToolResultError("The wiki root cannot be moved"), nil
+	}
+	if newPath != path && isUnderWikiPath(newPath, path) {
+		return mcp.NewToolResultError("A page cannot be moved below itself"), nil
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	wikiID := selectedWiki.Id.String()
+
+	versionDescriptor, err := wikiWriteVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Sub-pages move along with the page
+	tree, err := getWikiPageTree(ctx, wikiID, path, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	subPageCount := len(flattenWikiPages(*tree)) - 1
+
+	parameters := &wiki.WikiPageMoveParameters{
+		Path:    &path,
+		NewPath: &newPath,
+	}
+	if hasOrder {
+		newOrder := int(order)
+		parameters.NewOrder = &newOrder
+	}
+	moveArgs := wiki.CreatePageMoveArgs{
+		PageMoveParameters: parameters,
+		Project:            &config.Project,
+		WikiIdentifier:     &wikiID,
+		VersionDescriptor:  versionDescriptor,
+	}
+	if comment != "" {
+		moveArgs.Comment = &comment
+	}
+
+	if _, err := wikiClient.CreatePageMove(ctx, moveArgs); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to move wiki page: %v", err)), nil
+	}
+
+	var result strings.Builder
+	if newPath == path {
+		result.WriteString(fmt.Sprintf("Moved wiki page %s to position %d", path, int(order)))
+	} else {
+		result.WriteString(fmt.Sprintf("Moved wiki page %s to %s", path, newPath))
+		if subPageCount > 0 {
+			result.WriteString(fmt.Sprintf(" together with %d sub-page(s)", subPageCount))
+		}
+		if hasOrder {
+			result.WriteString(fmt.Sprintf(" at position %d", int(order)))
+		}
+	}
+	result.WriteString("\n")
+
+	if updateLinks && newPath != path {
+		updated, failed, err := rewriteInboundWikiLinks(ctx, selectedWiki, versionDescriptor, path, newPath)
+		if err != nil {
+			result.WriteString(fmt.Sprintf("\nThe page was moved, but links could not be updated: %v\n", err))
+			return mcp.NewToolResultText(result.String()), nil
+		}
+		if len(updated) == 0 {
+			result.WriteString("\nNo other pages linked to the moved pages.\n")
+		} else {
+			result.WriteString(fmt.Sprintf("\nUpdated links in %d page(s):\n- %s\n", len(updated), strings.Join(updated, "\n- ")))
+		}
+		if len(failed) > 0 {
+			result.WriteString(fmt.Sprintf("\nCould not update links in %d page(s):\n- %s\n", len(failed), strings.Join(failed, "\n- ")))
+		}
+	}
+
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// rewriteInboundWikiLinks fixes links that pointed into a moved page tree, including relative links
+// from moved pages to pages that stayed put. Returns the updated pages and those that failed.
+func rewriteInboundWikiLinks(ctx context.Context, w *wiki.WikiV2, versionDescriptor *git.GitVersionDescriptor, oldRoot, newRoot string) ([]string, []string, error) {
+	wikiID := w.Id.String()
+	pages, err := getWikiPagesWithContent(ctx, wikiID, "/", versionDescriptor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rewrite := func(content, newSource string) (string, int) {
+		oldSource := rebaseWikiPath(newSource, newRoot, oldRoot)
+		return scanMarkdownLinks(content, func(_ int, target string) (string, bool) {
+			link, ok := parseWikiLink(target, oldSource)
+			if !ok {
+				return "", false
+			}
+			want := rebaseWikiPath(link.Page, oldRoot, newRoot)
+			if now, ok := parseWikiLink(target, newSource); ok && strings.EqualFold(now.Page, want) {
+				return "", false
+			}
+			return formatWikiLink(link, want, newSource), true
+		})
+	}
+
+	var updated, failed []string
+	for _, page := range pages {
+		if page.Path == nil || *page.Path == "/" {
+			continue
+		}
+		newSource := *page.Path
+		if page.Content == nil {
+			failed = append(failed, fmt.Sprintf("%s: page could not be read", newSource))
+			continue
+		}
+		if _, replaced := rewrite(*page.Content, newSource); replaced == 0 {
+			continue
+		}
+
+		// Only pages that change are read again, for the ETag the write is guarded with
+		state, err := getWikiPageState(ctx, wikiID, newSource, versionDescriptor)
+		if err != nil || !state.Exists {
+			failed = append(failed, fmt.Sprintf("%s: page could not be read", newSource))
+			continue
+		}
+		content, replaced := rewrite(state.Content, newSource)
+		if replaced == 0 {
+			continue
+		}
+
+		_, err = writeWikiPage(ctx, wikiPageWrite{
+			Wiki:        w,
+			Path:        newSource,
+			Version:     versionDescriptor,
+			Content:     content,
+			ETag:        state.ETag,
+			BaseContent: &state.Content,
+			AutoMerge:   true,
+		})
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", newSource, err))
+			continue
+		}
+		updated = append(updated, fmt.Sprintf("%s (%d link(s))", newSource, replaced))
+	}
+	return updated, failed, nil
+}
+
+// Handler for deleting a wiki page and optionally its sub-pages
+func handleDeleteWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := normalizeWikiPath(request.Params.Arguments["path"].(string))
+	recursive, _ := request.Params.Arguments["recursive"].(bool)
+	confirm, _ := request.Params.Arguments["confirm"].(bool)
+	comment, _ := request.Params.Arguments["comment"].(string)
+
+	if path == "/" {
+		return mcp.NewToolResultError("The wiki root cannot be deleted"), nil
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	wikiID := selectedWiki.Id.String()
+
+	versionDescriptor, err := wikiWriteVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	tree, err := getWikiPageTree(ctx, wikiID, path, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var paths []string
+	for _, page := range flattenWikiPages(*tree) {
+		if page.Path != nil {
+			paths = append(paths, *page.Path)
+		}
+	}
+
+	if len(paths) > 1 && !recursive {
+		return mcp.NewToolResultError(fmt.Sprintf("Wiki page %s has %d sub-page(s):\n- %s\n\nPass recursive=true to delete them as well.",
+			path, len(paths)-1, strings.Join(paths[1:], "\n- "))), nil
+	}
+
+	if !confirm {
+		return mcp.NewToolResultText(fmt.Sprintf("%d page(s) would be deleted from wiki %s:\n- %s\n\nCall again with confirm=true to delete them.",
+			len(paths), *selectedWiki.Name, strings.Join(paths, "\n- "))), nil
+	}
+
+	// Delete the deepest pages first so no page is left without its parent
+	sort.SliceStable(paths, func(i, j int) bool {
+		return strings.Count(paths[i], "/") > strings.Count(paths[j], "/")
+	})
+
+	var deleted []string
+	for _, pagePath := range paths {
+		deleteArgs := wiki.DeletePageArgs{
+			Project:           &config.Project,
+			WikiIdentifier:    &wikiID,
+			Path:              &pagePath,
+			VersionDescriptor: versionDescriptor,
+		}
+		if comment != "" {
+			deleteArgs.Comment = &comment
+		}
+		if _, err := wikiClient.DeletePage(ctx, deleteArgs); err != nil {
+			message := fmt.Sprintf("Failed to delete wiki page %s: %v", pagePath, err)
+			if len(deleted) > 0 {
+				message += fmt.Sprintf("\n\nAlready deleted:\n- %s", strings.Join(deleted, "\n- "))
+			}
+			return mcp.NewToolResultError(message), nil
+		}
+		deleted = append(deleted, pagePath)
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Deleted %d wiki page(s):\n- %s", len(deleted), strings.Join(deleted, "\n- "))), nil
+}
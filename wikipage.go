@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+)
+
+// wikiPageState is the content and version of a wiki page as last read
+type wikiPageState struct {
+	Exists      bool
+	Content     string
+	ETag        string
+	GitItemPath string
+}
+
+// wikiPageWrite describes a guarded write of a wiki page
+type wikiPageWrite struct {
+	Wiki        *wiki.WikiV2
+	Path        string
+	Version     *git.GitVersionDescriptor
+	Content     string  // Proposed page content
+	ETag        string  // Page version the change is based on; empty to update whatever is current
+	BaseContent *string // Page content the change is based on, used for conflict diffs and merges
+	AutoMerge   bool    // Merge non-overlapping concurrent edits instead of reporting a conflict
+}
+
+// normalizeETag strips the weak marker and quotes so ETags can be compared
+func normalizeETag(eTag string) string {
+	return strings.Trim(strings.TrimPrefix(strings.TrimSpace(eTag), "W/"), `"`)
+}
+
+// getWikiPageState reads a page's content and ETag. A missing page is not an error.
+func getWikiPageState(ctx context.Context, wikiID, path string, versionDescriptor *git.GitVersionDescriptor) (wikiPageState, error) {
+	includeContent := true
+	response, err := wikiClient.GetPage(ctx, wiki.GetPageArgs{
+		Project:           &config.Project,
+		WikiIdentifier:    &wikiID,
+		Path:              &path,
+		VersionDescriptor: versionDescriptor,
+		IncludeContent:    &includeContent,
+	})
+	if err != nil {
+		if azureStatusCode(err) == http.StatusNotFound {
+			return wikiPageState{}, nil
+		}
+		return wikiPageState{}, fmt.Errorf("failed to read wiki page %s: %v", path, err)
+	}
+
+	state := wikiPageState{Exists: true}
+	if response.ETag != nil && len(*response.ETag) > 0 {
+		state.ETag = (*response.ETag)[0]
+	}
+	if response.Page != nil {
+		if response.Page.Content != nil {
+			state.Content = *response.Page.Content
+		}
+		if response.Page.GitItemPath != nil {
+			state.GitItemPath = *response.Page.GitItemPath
+		}
+	}
+	return state, nil
+}
+
+// getWikiPageAtETag tries to read the content a page had at an earlier ETag from the wiki history
+func getWikiPageAtETag(ctx context.Context, wikiID, path, eTag string) (*string, error) {
+	commit := normalizeETag(eTag)
+	state, err := getWikiPageState(ctx, wikiID, path, &git.GitVersionDescriptor{
+		Version:     &commit,
+		VersionType: &git.GitVersionTypeValues.Commit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !state.Exists {
+		return nil, fmt.Errorf("page %s not found at version %s", path, eTag)
+	}
+	return &state.Content, nil
+}
+
+// writeWikiPage creates or updates a page, passing the page ETag as If-Match so concurrent
+// edits are never overwritten. On a conflict the returned error holds a three-way diff.
+func writeWikiPage(ctx context.Context, write wikiPageWrite) (string, error) {
+	wikiID := write.Wiki.Id.String()
+
+	current, err := getWikiPageState(ctx, wikiID, write.Path, write.Version)
+	if err != nil {
+		return "", err
+	}
+
+	content := write.Content
+	merged := false
+	if current.Exists && write.ETag != "" && normalizeETag(write.ETag) != normalizeETag(current.ETag) {
+		base := write.BaseContent
+		if base == nil {
+			base, _ = getWikiPageAtETag(ctx, wikiID, write.Path, write.ETag)
+		}
+		content, err = resolveWikiConflict(write.Path, base, current, write.Content, write.ETag, write.AutoMerge)
+		if err != nil {
+			return "", err
+		}
+		merged = true
+	}
+
+	args := wiki.CreateOrUpdatePageArgs{
+		WikiIdentifier:    &wikiID,
+		Path:              &write.Path,
+		Project:           &config.Project,
+		VersionDescriptor: write.Version,
+		Parameters: &wiki.WikiPageCreateOrUpdateParameters{
+			Content: &content,
+		},
+	}
+	if current.Exists {
+		args.Version = &current.ETag
+	}
+
+	response, err := wikiClient.CreateOrUpdatePage(ctx, args)
+	if err != nil {
+		status := azureStatusCode(err)
+		if status == http.StatusPreconditionFailed || status == http.StatusConflict {
+			// The page changed between reading and writing it
+			latest, latestErr := getWikiPageState(ctx, wikiID, write.Path, write.Version)
+			if latestErr == nil && latest.Exists {
+				_, conflictErr := resolveWikiConflict(write.Path, &current.Content, latest, content, current.ETag, false)
+				return "", conflictErr
+			}
+		}
+		return "", fmt.Errorf("failed to save wiki page: %v", err)
+	}
+
+	action := "Updated"
+	if !current.Exists {
+		action = "Created"
+	}
+	result := fmt.Sprintf("%s wiki page: %s", action, write.Path)
+	if merged {
+		result += fmt.Sprintf("\nThe page had changed since version %s; your changes were merged with the current version %s.", write.ETag, current.ETag)
+	}
+	if response.ETag != nil && len(*response.ETag) > 0 {
+		result += fmt.Sprintf("\nNew version (ETag): %s", (*response.ETag)[0])
+	}
+	if response.Page != nil && response.Page.GitItemPath != nil {
+		result += "\n" + wikiRepositoryLocation(ctx, write.Wiki, write.Version, *response.Page.GitItemPath)
+	}
+	return result, nil
+}
+
+// resolveWikiConflict merges a proposed page with concurrent changes when allowed and possible,
+// and otherwise returns an error describing the conflict as a three-way diff
+func resolveWikiConflict(path string, base *string, current wikiPageState, proposed, baseETag string, autoMerge bool) (string, error) {
+	proposedLines := splitLines(proposed)
+	currentLines := splitLines(current.Content)
+
+	var mergedLines []string
+	conflicts := 0
+	var mergeErr error
+	if base != nil && autoMerge {
+		mergedLines, conflicts, mergeErr = mergeLines(splitLines(*base), proposedLines, currentLines, "proposed", "current "+current.ETag)
+		if mergeErr == nil && conflicts == 0 {
+			merged := strings.Join(mergedLines, "\n")
+			if strings.HasSuffix(proposed, "\n") {
+				merged += "\n"
+			}
+			return merged, nil
+		}
+	}
+
+	var report strings.Builder
+	report.WriteString(fmt.Sprintf("Conflict: wiki page %s was changed by someone else since version %s (current version %s). Nothing was saved.\n\n",
+		path, baseETag, current.ETag))
+
+	if base != nil {
+		baseLines := splitLines(*base)
+		report.WriteString("Changes made on the wiki since your base version:\n")
+		report.WriteString(diffOrNone(unifiedDiff("base "+baseETag, "current "+current.ETag, baseLines, currentLines, 3)))
+		report.WriteString("\nYour proposed changes:\n")
+		report.WriteString(diffOrNone(unifiedDiff("base "+baseETag, "proposed", baseLines, proposedLines, 3)))
+	} else {
+		report.WriteString("The base version could not be read, so only the current page and your proposed content are compared:\n")
+		report.WriteString(diffOrNone(unifiedDiff("current "+current.ETag, "proposed", currentLines, proposedLines, 3)))
+	}
+
+	switch {
+	case mergeErr != nil:
+		report.WriteString(fmt.Sprintf("\nAuto-merge was not possible: %v.\n", mergeErr))
+	case conflicts > 0:
+		report.WriteString(fmt.Sprintf("\nAuto-merge found %d overlapping change(s). Merge result with conflict markers:\n\n%s\n",
+			conflicts, strings.Join(mergedLines, "\n")))
+	case base != nil:
+		report.WriteString("\nPass auto_merge=true to merge non-overlapping changes automatically.\n")
+	}
+	report.WriteString(fmt.Sprintf("\nTo overwrite the current page, resend the content with etag=%s.", current.ETag))
+	return "", fmt.Errorf("%s", report.String())
+}
+
+// diffOrNone returns a diff, or a placeholder when there are no differences
+func diffOrNone(diff string) string {
+	if diff == "" {
+		return "(no changes)\n"
+	}
+	return diff
+}
+
+// getWikiPageTree reads a page and every page below it, without content
+func getWikiPageTree(ctx context.Context, wikiID, path string, versionDescriptor *git.GitVersionDescriptor) (*wiki.WikiPage, error) {
+	recursionLevel := git.VersionControlRecursionTypeValues.Full
+	response, err := wikiClient.GetPage(ctx, wiki.GetPageArgs{
+		Project:           &config.Project,
+		WikiIdentifier:    &wikiID,
+		Path:              &path,
+		RecursionLevel:    &recursionLevel,
+		VersionDescriptor: versionDescriptor,
+	})
+	if err != nil {
+		if azureStatusCode(err) == http.StatusNotFound {
+			return nil, fmt.Errorf("wiki page %s not found", path)
+		}
+		return nil, fmt.Errorf("failed to read wiki pages under %s: %v", path, err)
+	}
+	if response.Page == nil {
+		return nil, fmt.Errorf("wiki page %s not found", path)
+	}
+	return response.Page, nil
+}
+
+// flattenWikiPages returns a page and all of its sub-pages, parents before children
+func flattenWikiPages(page wiki.WikiPage) []wiki.WikiPage {
+	pages := []wiki.WikiPage{page}
+	if page.SubPages != nil {
+		for _, subPage := range *page.SubPages {
+			pages = append(pages, flattenWikiPages(subPage)...)
+		}
+	}
+	return pages
+}
+
+// normalizeWikiPath makes a wiki page path absolute and drops any trailing slash
+func normalizeWikiPath(path string) string {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
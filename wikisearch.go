@@ -0,0 +1,416 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+)
+
+// Matching lines shown per page, and the longest snippet shown for a line
+const (
+	maxSearchLinesPerPage  = 3
+	maxSearchSnippetLength = 200
+)
+
+// wikiSearchResult is a page matching a wiki search
+type wikiSearchResult struct {
+	Path       string
+	Score      float64
+	Highlights []string // Snippets highlighted by the search service
+	Lines      []wikiSearchLine
+}
+
+// wikiSearchLine is a line of a page containing search terms
+type wikiSearchLine struct {
+	Number int
+	Text   string
+}
+
+// Handler for searching the content of wiki pages
+func handleSearchWiki(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := strings.TrimSpace(request.Params.Arguments["query"].(string))
+	path, _ := request.Params.Arguments["path"].(string)
+	branch, _ := request.Params.Arguments["branch"].(string)
+	version, _ := request.Params.Arguments["version"].(string)
+	top := 10
+	if value, ok := request.Params.Arguments["top"].(float64); ok && value > 0 {
+		top = int(value)
+	}
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return mcp.NewToolResultError("The query must contain at least one word"), nil
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	wikiID := selectedWiki.Id.String()
+
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	root := "/"
+	if path != "" {
+		root = normalizeWikiPath(path)
+	}
+
+	// The search service only indexes the published version of a wiki
+	var results []wikiSearchResult
+	var note string
+	fromService := false
+	if branch == "" && version == "" {
+		results, err = searchWikiService(ctx, selectedWiki, query, root, top)
+		if err == nil {
+			fromService = true
+		} else {
+			log.Printf("Wiki search service unavailable, scanning page content: %v", err)
+			note = fmt.Sprintf("The wiki search service is not available (%v), so page content was scanned instead.\n\n", err)
+		}
+	}
+	if !fromService {
+		results, err = scanWikiContent(ctx, wikiID, versionDescriptor, root, query, terms)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to search wiki: %v", err)), nil
+		}
+	}
+
+	if len(results) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("%sNo matches found for '%s'", note, query)), nil
+	}
+	if len(results) > top {
+		results = results[:top]
+	}
+
+	// The search service returns highlights without positions, so find the matching lines
+	if fromService {
+		for i := range results {
+			state, err := getWikiPageState(ctx, wikiID, results[i].Path, versionDescriptor)
+			if err == nil && state.Exists {
+				results[i].Lines = matchingLines(state.Content, terms)
+			}
+		}
+	}
+
+	var output strings.Builder
+	output.WriteString(note)
+	source := "page content scan"
+	if fromService {
+		source = "Azure DevOps search"
+	}
+	output.WriteString(fmt.Sprintf("Found %d matching page(s) for '%s' in wiki %s (%s):\n", len(results), query, *selectedWiki.Name, source))
+	for i, result := range results {
+		output.WriteString(fmt.Sprintf("\n%d. %s\n", i+1, result.Path))
+		for _, highlight := range result.Highlights {
+			output.WriteString(fmt.Sprintf("   > %s\n", highlight))
+		}
+		for _, line := range result.Lines {
+			output.WriteString(fmt.Sprintf("   Line %d: %s\n", line.Number, line.Text))
+		}
+	}
+
+	return mcp.NewToolResultText(output.String()), nil
+}
+
+// searchWikiService queries the Azure DevOps wiki search API, which lives on the almsearch host
+func searchWikiService(ctx context.Context, w *wiki.WikiV2, query, root string, top int) ([]wikiSearchResult, error) {
+	base := strings.Replace(config.OrganizationURL, "https://dev.azure.com/", "https://almsearch.dev.azure.com/", 1)
+	fullURL := fmt.Sprintf("%s/%s/_apis/search/wikisearchresults?api-version=7.1", base, url.PathEscape(config.Project))
+
+	// Path filtering happens here, so fetch extra results when searching a subtree
+	fetch := top
+	if root != "/" {
+		fetch = max(top, 200)
+	}
+	payload := map[string]interface{}{
+		"searchText": query,
+		"$skip":      0,
+		"$top":       fetch,
+		"filters": map[string][]string{
+			"Project": {config.Project},
+			"Wiki":    {*w.Name},
+		},
+		"includeFacets": false,
+	}
+
+	var response struct {
+		Count   int `json:"count"`
+		Results []struct {
+			Path string `json:"path"`
+			Wiki struct {
+				ID string `json:"id"`
+			} `json:"wiki"`
+			Hits []struct {
+				FieldReferenceName string   `json:"fieldReferenceName"`
+				Highlights         []string `json:"highlights"`
+			} `json:"hits"`
+		} `json:"results"`
+	}
+	if err := doAzureDevOpsJSON(ctx, http.MethodPost, fullURL, payload, &response); err != nil {
+		return nil, err
+	}
+
+	var results []wikiSearchResult
+	for i, item := range response.Results {
+		if item.Wiki.ID != "" && !strings.EqualFold(item.Wiki.ID, w.Id.String()) {
+			continue
+		}
+		pagePath := wikiPagePathFromGitPath(w, item.Path)
+		if !isUnderWikiPath(pagePath, root) {
+			continue
+		}
+		result := wikiSearchResult{Path: pagePath, Score: float64(len(response.Results) - i)}
+		for _, hit := range item.Hits {
+			for _, highlight := range hit.Highlights {
+				result.Highlights = append(result.Highlights, formatSearchHighlight(highlight))
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// wikiPagePathFromGitPath converts the path of a page's markdown file to its wiki page path
+func wikiPagePathFromGitPath(w *wiki.WikiV2, gitPath string) string {
+	if w.MappedPath != nil && *w.MappedPath != "/" && isUnderWikiPath(gitPath, *w.MappedPath) {
+		gitPath = gitPath[len(strings.TrimSuffix(*w.MappedPath, "/")):]
+	}
+	if link, ok := parseWikiLink(gitPath, "/"); ok {
+		return link.Page
+	}
+	return gitPath
+}
+
+// formatSearchHighlight turns the search service's highlight tags into markdown bold
+func formatSearchHighlight(highlight string) string {
+	highlight = strings.ReplaceAll(highlight, "<highlighthit>", "**")
+	highlight = strings.ReplaceAll(highlight, "</highlighthit>", "**")
+	return strings.Join(strings.Fields(highlight), " ")
+}
+
+// scanWikiContent searches the content of every page under root and ranks the matching pages
+func scanWikiContent(ctx context.Context, wikiID string, versionDescriptor *git.GitVersionDescriptor, root, query string, terms []string) ([]wikiSearchResult, error) {
+	pages, err := getWikiPagesWithContent(ctx, wikiID, root, versionDescriptor)
+	if err != nil {
+		return nil, err
+	}
+
+	phrase := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	var results []wikiSearchResult
+	for _, page := range pages {
+		if page.Path == nil || page.Content == nil {
+			continue
+		}
+		content := strings.ToLower(*page.Content)
+		title := strings.ToLower(*page.Path)
+
+		// Pages matching more of the terms rank first, then by term frequency and title matches
+		matched := 0
+		score := 0.0
+		for _, term := range terms {
+			count := strings.Count(content, term)
+			inTitle := strings.Contains(title, term)
+			if count == 0 && !inTitle {
+				continue
+			}
+			matched++
+			if count > 0 {
+				score += 1 + math.Log(float64(count))
+			}
+			if inTitle {
+				score += 3
+			}
+		}
+		if matched == 0 {
+			continue
+		}
+		if len(terms) > 1 {
+			score += 2 * float64(strings.Count(content, phrase))
+		}
+		score += 10 * float64(matched)
+
+		results = append(results, wikiSearchResult{
+			Path:  *page.Path,
+			Score: score,
+			Lines: matchingLines(*page.Content, terms),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results, nil
+}
+
+// getWikiPagesWithContent reads every page under root with its content. Pages the recursive
+// request returned without content are read one by one.
+func getWikiPagesWithContent(ctx context.Context, wikiID, root string, versionDescriptor *git.GitVersionDescriptor) ([]wiki.WikiPage, error) {
+	queryParams := url.Values{}
+	queryParams.Add("path", root)
+	queryParams.Add("recursionLevel", "full")
+	queryParams.Add("includeContent", "true")
+	addWikiVersionParams(queryParams, versionDescriptor)
+	queryParams.Add("api-version", "7.2-preview")
+	fullURL := projectAPIURL("wiki/wikis/%s/pages?%s", wikiID, queryParams.Encode())
+
+	var rootPage wiki.WikiPage
+	if err := doAzureDevOpsJSON(ctx, http.MethodGet, fullURL, nil, &rootPage); err != nil {
+		return nil, fmt.Errorf("failed to read wiki pages under %s: %v", root, err)
+	}
+
+	pages := flattenWikiPages(rootPage)
+	for i, page := range pages {
+		if page.Content != nil || page.Path == nil {
+			continue
+		}
+		state, err := getWikiPageState(ctx, wikiID, *page.Path, versionDescriptor)
+		if err != nil || !state.Exists {
+			continue
+		}
+		content := state.Content
+		pages[i].Content = &content
+	}
+	return pages, nil
+}
+
+// searchTerms splits a query into distinct lower-case words
+func searchTerms(query string) []string {
+	seen := map[string]bool{}
+	var terms []string
+	for _, word := range tokenizeText(query) {
+		if !seen[word] {
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// tokenizeText splits text into lower-case words
+func tokenizeText(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchingLines returns the lines containing the most search terms, in page order, with the terms highlighted
+func matchingLines(content string, terms []string) []wikiSearchLine {
+	type candidate struct {
+		number  int
+		text    string
+		matched int
+	}
+	var candidates []candidate
+	for i, line := range splitLines(content) {
+		lower := strings.ToLower(line)
+		matched := 0
+		for _, term := range terms {
+			if strings.Contains(lower, term) {
+				matched++
+			}
+		}
+		if matched > 0 {
+			candidates = append(candidates, candidate{number: i + 1, text: line, matched: matched})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].matched > candidates[j].matched
+	})
+	if len(candidates) > maxSearchLinesPerPage {
+		candidates = candidates[:maxSearchLinesPerPage]
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].number < candidates[j].number
+	})
+
+	lines := make([]wikiSearchLine, 0, len(candidates))
+	for _, c := range candidates {
+		lines = append(lines, wikiSearchLine{Number: c.number, Text: highlightTerms(clipSnippet(strings.TrimSpace(c.text), terms), terms)})
+	}
+	return lines
+}
+
+// clipSnippet shortens a long line to a window around the first search term
+func clipSnippet(text string, terms []string) string {
+	if len(text) <= maxSearchSnippetLength {
+		return text
+	}
+	lower := strings.ToLower(text)
+	first := len(text)
+	for _, term := range terms {
+		if i := strings.Index(lower, term); i != -1 && i < first {
+			first = i
+		}
+	}
+	start := max(0, first-maxSearchSnippetLength/3)
+	end := min(len(text), start+maxSearchSnippetLength)
+	// Avoid cutting multi-byte characters in half
+	for start > 0 && !isRuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+
+	snippet := text[start:end]
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(text) {
+		snippet += "..."
+	}
+	return snippet
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// highlightTerms wraps every occurrence of the search terms in markdown bold
+func highlightTerms(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// Offsets only line up when lower-casing keeps the byte length
+		return text
+	}
+
+	marked := make([]bool, len(text))
+	for _, term := range terms {
+		for offset := 0; ; {
+			i := strings.Index(lower[offset:], term)
+			if i == -1 {
+				break
+			}
+			for j := offset + i; j < offset+i+len(term); j++ {
+				marked[j] = true
+			}
+			offset += i + len(term)
+		}
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(text); i++ {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			out.WriteString("**")
+		}
+		out.WriteByte(text[i])
+		if marked[i] && (i == len(text)-1 || !marked[i+1]) {
+			out.WriteString("**")
+		}
+	}
+	return out.String()
+}
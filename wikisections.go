@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// markdownSection is a heading and the lines up to the next heading of the same or a higher level
+type markdownSection struct {
+	Level  int
+	Title  string
+	Path   []string // Titles of the enclosing headings and this one
+	Anchor string
+	Start  int // Line index of the heading
+	End    int // Line index after the section, including its sub-sections
+}
+
+// parseMarkdownSections finds the ATX headings outside code blocks. Repeated anchors get -1, -2
+// and so on, like the wiki generates them.
+func parseMarkdownSections(lines []string) []markdownSection {
+	var sections []markdownSection
+	var open []int // Indexes of the sections enclosing the current line
+	seen := map[string]int{}
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := markdownHeadingPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		level := len(m[1])
+		for len(open) > 0 && sections[open[len(open)-1]].Level >= level {
+			sections[open[len(open)-1]].End = i
+			open = open[:len(open)-1]
+		}
+
+		var path []string
+		if len(open) > 0 {
+			path = append(path, sections[open[len(open)-1]].Path...)
+		}
+		anchor := wikiHeadingAnchor(m[2])
+		if count := seen[anchor]; count > 0 {
+			seen[anchor]++
+			anchor = fmt.Sprintf("%s-%d", anchor, count)
+		} else {
+			seen[anchor]++
+		}
+
+		sections = append(sections, markdownSection{
+			Level:  level,
+			Title:  m[2],
+			Path:   append(path, m[2]),
+			Anchor: anchor,
+			Start:  i,
+		})
+		open = append(open, len(sections)-1)
+	}
+	for _, index := range open {
+		sections[index].End = len(lines)
+	}
+	return sections
+}
+
+// findMarkdownSection finds a section by anchor (#setup) or by heading path ("Setup > Linux"),
+// where the path may leave out enclosing headings
+func findMarkdownSection(sections []markdownSection, ref string) (markdownSection, error) {
+	ref = strings.TrimSpace(ref)
+	var matches []markdownSection
+	// "#setup" is an anchor, "## Setup" a heading
+	if strings.HasPrefix(ref, "#") && !strings.HasPrefix(strings.TrimLeft(ref, "#"), " ") {
+		anchor := strings.ToLower(strings.TrimPrefix(ref, "#"))
+		for _, section := range sections {
+			if section.Anchor == anchor {
+				return section, nil
+			}
+		}
+	} else {
+		var want []string
+		for _, part := range strings.Split(ref, ">") {
+			want = append(want, strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(part), "#")))
+		}
+		for _, section := range sections {
+			if len(section.Path) < len(want) {
+				continue
+			}
+			tail := section.Path[len(section.Path)-len(want):]
+			matched := true
+			for i := range want {
+				if !strings.EqualFold(strings.TrimSpace(tail[i]), want[i]) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				matches = append(matches, section)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		var available []string
+		for _, section := range sections {
+			available = append(available, strings.Join(section.Path, " > "))
+		}
+		if len(available) == 0 {
+			return markdownSection{}, fmt.Errorf("section %s not found, the page has no headings", ref)
+		}
+		return markdownSection{}, fmt.Errorf("section %s not found, available sections:\n- %s", ref, strings.Join(available, "\n- "))
+	}
+	var candidates []string
+	for _, section := range matches {
+		candidates = append(candidates, fmt.Sprintf("%s (#%s, line %d)", strings.Join(section.Path, " > "), section.Anchor, section.Start+1))
+	}
+	return markdownSection{}, fmt.Errorf("section %s is ambiguous, use the full heading path or the anchor:\n- %s", ref, strings.Join(candidates, "\n- "))
+}
+
+// sectionBodyEnd returns the line index where a section's own text ends, before its first
+// sub-section
+func sectionBodyEnd(sections []markdownSection, section markdownSection) int {
+	for _, other := range sections {
+		if other.Start > section.Start && other.Start < section.End {
+			return other.Start
+		}
+	}
+	return section.End
+}
+
+// spliceLines replaces lines[start:end] with block, keeping a blank line between the block and
+// adjacent text
+func spliceLines(lines []string, start, end int, block []string) []string {
+	isBlank := func(line string) bool { return strings.TrimSpace(line) == "" }
+
+	var result []string
+	result = append(result, lines[:start]...)
+	if len(block) > 0 {
+		if start > 0 && !isBlank(lines[start-1]) && !isBlank(block[0]) {
+			result = append(result, "")
+		}
+		result = append(result, block...)
+		if end < len(lines) && !isBlank(lines[end]) && !isBlank(block[len(block)-1]) {
+			result = append(result, "")
+		}
+	}
+	return append(result, lines[end:]...)
+}
+
+// Handler for listing the headings of a wiki page
+func handleGetWikiPageOutline(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := normalizeWikiPath(request.Params.Arguments["path"].(string))
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	state, err := getWikiPageState(ctx, selectedWiki.Id.String(), path, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if !state.Exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Wiki page %s not found", path)), nil
+	}
+
+	lines := splitLines(state.Content)
+	sections := parseMarkdownSections(lines)
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Outline of %s (%d lines)\nVersion (ETag): %s\n\n", path, len(lines), state.ETag))
+	if len(sections) == 0 {
+		result.WriteString("The page has no headings.")
+		return mcp.NewToolResultText(result.String()), nil
+	}
+	for _, section := range sections {
+		result.WriteString(fmt.Sprintf("%s- %s %s (lines %d-%d, #%s)\n",
+			strings.Repeat("  ", len(section.Path)-1), strings.Repeat("#", section.Level), section.Title,
+			section.Start+1, section.End, section.Anchor))
+	}
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// Handler for reading one section of a wiki page
+func handleGetWikiPageSection(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := normalizeWikiPath(request.Params.Arguments["path"].(string))
+	sectionRef := request.Params.Arguments["section"].(string)
+	includeSubsections := true
+	if value, ok := request.Params.Arguments["include_subsections"].(bool); ok {
+		includeSubsections = value
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	state, err := getWikiPageState(ctx, selectedWiki.Id.String(), path, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if !state.Exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Wiki page %s not found", path)), nil
+	}
+
+	lines := splitLines(state.Content)
+	sections := parseMarkdownSections(lines)
+	section, err := findMarkdownSection(sections, sectionRef)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	end := section.End
+	if !includeSubsections {
+		end = sectionBodyEnd(sections, section)
+	}
+
+	header := fmt.Sprintf("Section %s of %s, lines %d-%d\nVersion (ETag): %s\n\n",
+		strings.Join(section.Path, " > "), path, section.Start+1, end, state.ETag)
+	return mcp.NewToolResultText(header + strings.Join(lines[section.Start:end], "\n")), nil
+}
+
+// Handler for replacing, appending to or inserting a section of a wiki page
+func handleUpdateWikiPageSection(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	path := normalizeWikiPath(request.Params.Arguments["path"].(string))
+	sectionRef, _ := request.Params.Arguments["section"].(string)
+	operation := request.Params.Arguments["operation"].(string)
+	content := request.Params.Arguments["content"].(string)
+	eTag, _ := request.Params.Arguments["etag"].(string)
+	autoMerge, _ := request.Params.Arguments["auto_merge"].(bool)
+
+	if sectionRef == "" && operation != "append" {
+		return mcp.NewToolResultError("section is required, except when appending to the end of the page"), nil
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	wikiID := selectedWiki.Id.String()
+	versionDescriptor, err := wikiWriteVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	current, err := getWikiPageState(ctx, wikiID, path, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if !current.Exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Wiki page %s not found", path)), nil
+	}
+
+	// Edit the version the caller read, so concurrent changes elsewhere on the page can be merged
+	base := current.Content
+	var baseContent *string
+	if eTag == "" || normalizeETag(eTag) == normalizeETag(current.ETag) {
+		eTag = current.ETag
+		baseContent = &current.Content
+	} else if older, err := getWikiPageAtETag(ctx, wikiID, path, eTag); err == nil {
+		base = *older
+		baseContent = older
+	}
+
+	lines := splitLines(base)
+	block := splitLines(content)
+
+	var updated []string
+	description := ""
+	if sectionRef == "" {
+		updated = spliceLines(lines, len(lines), len(lines), block)
+		description = "Appended to the end of the page"
+	} else {
+		sections := parseMarkdownSections(lines)
+		section, err := findMarkdownSection(sections, sectionRef)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		name := strings.Join(section.Path, " > ")
+
+		switch operation {
+		case "replace":
+			// Keep the heading when the new content does not bring its own
+			start := section.Start
+			if len(block) == 0 || markdownHeadingPattern.FindStringSubmatch(block[0]) == nil {
+				start++
+			}
+			updated = spliceLines(lines, start, section.End, block)
+			description = fmt.Sprintf("Replaced section %s", name)
+		case "append":
+			// Append to the section's own text, before any sub-sections
+			end := sectionBodyEnd(sections, section)
+			updated = spliceLines(lines, end, end, block)
+			description = fmt.Sprintf("Appended to section %s", name)
+		case "insert_before":
+			updated = spliceLines(lines, section.Start, section.Start, block)
+			description = fmt.Sprintf("Inserted before section %s", name)
+		case "insert_after":
+			updated = spliceLines(lines, section.End, section.End, block)
+			description = fmt.Sprintf("Inserted after section %s", name)
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("Unknown operation %s, use replace, append, insert_before or insert_after", operation)), nil
+		}
+	}
+
+	newContent := strings.Join(updated, "\n")
+	if strings.HasSuffix(base, "\n") || base == "" {
+		newContent += "\n"
+	}
+
+	result, err := writeWikiPage(ctx, wikiPageWrite{
+		Wiki:        selectedWiki,
+		Path:        path,
+		Version:     versionDescriptor,
+		Content:     newContent,
+		ETag:        eTag,
+		BaseContent: baseContent,
+		AutoMerge:   autoMerge,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(description + "\n" + result), nil
+}
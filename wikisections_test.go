@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testSectionPage = splitLines(strings.Join([]string{
+	"Intro",         // 0
+	"# Setup",       // 1
+	"Install it.",   // 2
+	"## Linux",      // 3
+	"Use apt.",      // 4
+	"```",           // 5
+	"# not a title", // 6
+	"```",           // 7
+	"## Windows",    // 8
+	"Use winget.",   // 9
+	"# Usage",       // 10
+	"## Linux",      // 11
+	"Run it.",       // 12
+}, "\n"))
+
+func TestParseMarkdownSections(t *testing.T) {
+	var got []string
+	for _, section := range parseMarkdownSections(testSectionPage) {
+		got = append(got, fmt.Sprintf("%s|%s|%d|%d-%d", strings.Join(section.Path, " > "), section.Anchor, section.Level, section.Start, section.End))
+	}
+	want := []string{
+		"Setup|setup|1|1-10",
+		"Setup > Linux|linux|2|3-8",
+		"Setup > Windows|windows|2|8-10",
+		"Usage|usage|1|10-13",
+		"Usage > Linux|linux-1|2|11-13",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMarkdownSections =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestFindMarkdownSection(t *testing.T) {
+	sections := parseMarkdownSections(testSectionPage)
+	tests := []struct {
+		ref     string
+		start   int
+		wantErr string
+	}{
+		{"Setup", 1, ""},
+		{"## Windows", 8, ""},
+		{"setup > linux", 3, ""},
+		{"Usage > Linux", 11, ""},
+		{"#linux-1", 11, ""},
+		{"#USAGE", 10, ""},
+		{"Linux", 0, "ambiguous"},
+		{"Missing", 0, "not found"},
+		{"#missing", 0, "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			section, err := findMarkdownSection(sections, tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("findMarkdownSection(%q) error = %v, want %q", tt.ref, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || section.Start != tt.start {
+				t.Errorf("findMarkdownSection(%q) = line %d, %v, want line %d", tt.ref, section.Start, err, tt.start)
+			}
+		})
+	}
+}
+
+func TestSectionBodyEnd(t *testing.T) {
+	sections := parseMarkdownSections(testSectionPage)
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{"Setup", 3},
+		{"Setup > Linux", 8},
+		{"Usage", 11},
+		{"Usage > Linux", 13},
+	}
+	for _, tt := range tests {
+		section, err := findMarkdownSection(sections, tt.ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sectionBodyEnd(sections, section); got != tt.want {
+			t.Errorf("sectionBodyEnd(%q) = %d, want %d", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestSpliceLines(t *testing.T) {
+	tests := []struct {
+		name       string
+		lines      string
+		start, end int
+		block      string
+		want       string
+	}{
+		{"insert between text", "a|b", 1, 1, "x", "a||x||b"},
+		{"insert next to blank lines", "a||b", 2, 2, "x", "a||x||b"},
+		{"replace", "a|b|c", 1, 2, "x", "a||x||c"},
+		{"delete", "a|b|c", 1, 2, "", "a|c"},
+		{"append at end", "a", 1, 1, "x", "a||x"},
+		{"insert at start", "a", 0, 0, "x", "x||a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var block []string
+			if tt.block != "" {
+				block = strings.Split(tt.block, "|")
+			}
+			got := strings.Join(spliceLines(strings.Split(tt.lines, "|"), tt.start, tt.end, block), "|")
+			if got != tt.want {
+				t.Errorf("spliceLines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
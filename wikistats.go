@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+)
+
+// The page stats API only keeps views for the last 30 days
+const maxWikiPageViewDays = 30
+
+// wikiPageStats is the view count and last edit of a page
+type wikiPageStats struct {
+	Path       string
+	Views      int
+	LastEdited *time.Time
+	Editor     string
+}
+
+// wikiPageViewsBetween sums the daily views whose day falls between from and to, both dates inclusive
+func wikiPageViewsBetween(stats []wiki.WikiPageStat, from, to time.Time) int {
+	first, last := from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02")
+	total := 0
+	for _, stat := range stats {
+		if stat.Day == nil || stat.Count == nil {
+			continue
+		}
+		if day := stat.Day.Time.UTC().Format("2006-01-02"); day >= first && day <= last {
+			total += *stat.Count
+		}
+	}
+	return total
+}
+
+// getWikiPageViews returns the views of every page between from and to, keyed by page path
+func getWikiPageViews(ctx context.Context, w *wiki.WikiV2, versionDescriptor *git.GitVersionDescriptor, from, to time.Time) (map[string]int, error) {
+	wikiID := w.Id.String()
+	// Stats come per day for the last days up to today, so ask for enough days to reach from
+	days := wikiPageViewDaysSince(from, time.Now())
+	if days < 1 {
+		days = 1
+	}
+	top := 100
+	views := map[string]int{}
+	continuationToken := ""
+	for {
+		batchRequest := &wiki.WikiPagesBatchRequest{
+			Top:              &top,
+			PageViewsForDays: &days,
+		}
+		if continuationToken != "" {
+			batchRequest.ContinuationToken = &continuationToken
+		}
+		response, err := wikiClient.GetPagesBatch(ctx, wiki.GetPagesBatchArgs{
+			PagesBatchRequest: batchRequest,
+			Project:           &config.Project,
+			WikiIdentifier:    &wikiID,
+			VersionDescriptor: versionDescriptor,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get wiki page stats: %v", err)
+		}
+		for _, page := range response.Value {
+			if page.Path == nil {
+				continue
+			}
+			if page.ViewStats != nil {
+				views[*page.Path] = wikiPageViewsBetween(*page.ViewStats, from, to)
+			}
+		}
+		if response.ContinuationToken == "" || len(response.Value) == 0 {
+			return views, nil
+		}
+		continuationToken = response.ContinuationToken
+	}
+}
+
+// wikiPageViewDaysSince counts the days from the date of from to the date of now, both inclusive
+func wikiPageViewDaysSince(from, now time.Time) int {
+	first := time.Date(from.UTC().Year(), from.UTC().Month(), from.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	return int(today.Sub(first).Hours()/24) + 1
+}
+
+// getWikiPageLastEdits returns the commit that last changed each page's file, keyed by page path
+func getWikiPageLastEdits(ctx context.Context, w *wiki.WikiV2, versionDescriptor *git.GitVersionDescriptor) (map[string]git.GitCommitRef, error) {
+	if w.RepositoryId == nil {
+		return nil, fmt.Errorf("wiki %s has no backing repository", *w.Name)
+	}
+	repositoryID := w.RepositoryId.String()
+	scopePath := "/"
+	if w.MappedPath != nil && *w.MappedPath != "" {
+		scopePath = *w.MappedPath
+	}
+	recursionLevel := git.VersionControlRecursionTypeValues.Full
+	latestProcessedChange := true
+	items, err := gitClient.GetItems(ctx, git.GetItemsArgs{
+		RepositoryId:          &repositoryID,
+		Project:               &config.Project,
+		ScopePath:             &scopePath,
+		RecursionLevel:        &recursionLevel,
+		LatestProcessedChange: &latestProcessedChange,
+		VersionDescriptor:     versionDescriptor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wiki files: %v", err)
+	}
+
+	edits := map[string]git.GitCommitRef{}
+	for _, item := range *items {
+		if item.Path == nil || item.LatestProcessedChange == nil || (item.IsFolder != nil && *item.IsFolder) {
+			continue
+		}
+		if !strings.HasSuffix(strings.ToLower(*item.Path), ".md") || strings.Contains(*item.Path, "/.attachments/") {
+			continue
+		}
+		edits[wikiPagePathFromGitPath(w, *item.Path)] = *item.LatestProcessedChange
+	}
+	return edits, nil
+}
+
+// Handler for reporting page views and last edits of a wiki subtree
+func handleGetWikiPageStats(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rootPath, _ := request.Params.Arguments["path"].(string)
+	days := maxWikiPageViewDays
+	value, hasDays := request.Params.Arguments["days"].(float64)
+	if hasDays && value > 0 {
+		days = int(value)
+	}
+	fromArg, _ := request.Params.Arguments["from"].(string)
+	toArg, _ := request.Params.Arguments["to"].(string)
+	sortBy, _ := request.Params.Arguments["sort"].(string)
+	top := 50
+	if value, ok := request.Params.Arguments["top"].(float64); ok && value > 0 {
+		top = int(value)
+	}
+	staleDays := 0
+	if value, ok := request.Params.Arguments["stale_days"].(float64); ok && value > 0 {
+		staleDays = int(value)
+	}
+
+	if days > maxWikiPageViewDays {
+		return mcp.NewToolResultError(fmt.Sprintf("days must be between 1 and %d; Azure DevOps keeps page views for %d days", maxWikiPageViewDays, maxWikiPageViewDays)), nil
+	}
+	if hasDays && (fromArg != "" || toArg != "") {
+		return mcp.NewToolResultError("use either days or from/to, not both"), nil
+	}
+	now := time.Now()
+	to := now
+	from := now.AddDate(0, 0, 1-days)
+	if fromArg != "" {
+		parsed, err := parseDateArg(fromArg)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		from = parsed
+	}
+	if toArg != "" {
+		parsed, err := parseDateArg(toArg)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		to = parsed
+	}
+	if wikiPageViewDaysSince(from, now) > maxWikiPageViewDays {
+		return mcp.NewToolResultError(fmt.Sprintf("from must be within the last %d days; Azure DevOps keeps page views for %d days", maxWikiPageViewDays, maxWikiPageViewDays)), nil
+	}
+	if to.Before(from) {
+		return mcp.NewToolResultError("to must not be before from"), nil
+	}
+	root := "/"
+	if rootPath != "" {
+		root = normalizeWikiPath(rootPath)
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	tree, err := getWikiPageTree(ctx, selectedWiki.Id.String(), root, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	views, err := getWikiPageViews(ctx, selectedWiki, versionDescriptor, from, to)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	edits, err := getWikiPageLastEdits(ctx, selectedWiki, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var stats []wikiPageStats
+	for _, page := range flattenWikiPages(*tree) {
+		if page.Path == nil || *page.Path == "/" {
+			continue
+		}
+		stat := wikiPageStats{Path: *page.Path, Views: views[*page.Path]}
+		if commit, ok := edits[*page.Path]; ok && commit.Author != nil {
+			if commit.Author.Date != nil {
+				edited := commit.Author.Date.Time
+				stat.LastEdited = &edited
+			}
+			stat.Editor = stringOrEmpty(commit.Author.Name)
+		}
+		if staleDays > 0 && stat.LastEdited != nil && now.Sub(*stat.LastEdited) < time.Duration(staleDays)*24*time.Hour {
+			continue
+		}
+		stats = append(stats, stat)
+	}
+
+	// Pages without a known edit date sort as the most outdated
+	editedBefore := func(a, b wikiPageStats) bool {
+		if a.LastEdited == nil || b.LastEdited == nil {
+			return a.LastEdited == nil && b.LastEdited != nil
+		}
+		return a.LastEdited.Before(*b.LastEdited)
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		switch sortBy {
+		case "most_visited":
+			if a.Views != b.Views {
+				return a.Views > b.Views
+			}
+		case "most_outdated":
+			if editedBefore(a, b) || editedBefore(b, a) {
+				return editedBefore(a, b)
+			}
+			if a.Views != b.Views {
+				return a.Views < b.Views
+			}
+		default:
+			if a.Views != b.Views {
+				return a.Views < b.Views
+			}
+			if editedBefore(a, b) || editedBefore(b, a) {
+				return editedBefore(a, b)
+			}
+		}
+		return a.Path < b.Path
+	})
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Page views from %s to %s and last edits in wiki %s under %s",
+		from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02"), *selectedWiki.Name, root))
+	if staleDays > 0 {
+		result.WriteString(fmt.Sprintf(", pages not edited for %d day(s)", staleDays))
+	}
+	result.WriteString(fmt.Sprintf(" (%d page(s)):\n\n", len(stats)))
+	if len(stats) == 0 {
+		result.WriteString("No pages found.")
+		return mcp.NewToolResultText(result.String()), nil
+	}
+
+	result.WriteString("| Views | Last edited | Days since edit | Edited by | Page |\n")
+	result.WriteString("|---|---|---|---|---|\n")
+	for i, stat := range stats {
+		if i == top {
+			result.WriteString(fmt.Sprintf("\n... and %d more page(s)\n", len(stats)-i))
+			break
+		}
+		edited, age := "unknown", "-"
+		if stat.LastEdited != nil {
+			edited = stat.LastEdited.Format("2006-01-02")
+			age = fmt.Sprintf("%d", int(now.Sub(*stat.LastEdited).Hours()/24))
+		}
+		result.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s |\n", stat.Views, edited, age, stat.Editor, stat.Path))
+	}
+	return mcp.NewToolResultText(result.String()), nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+)
+
+// testPageStat builds the views of one day
+func testPageStat(day string, count int) wiki.WikiPageStat {
+	parsed, _ := time.Parse("2006-01-02", day)
+	return wiki.WikiPageStat{Day: &azuredevops.Time{Time: parsed}, Count: &count}
+}
+
+func TestWikiPageViewsBetween(t *testing.T) {
+	stats := []wiki.WikiPageStat{
+		testPageStat("2026-10-01", 1),
+		testPageStat("2026-10-02", 2),
+		testPageStat("2026-10-03", 4),
+		{Count: new(int)},
+	}
+	tests := []struct {
+		from, to string
+		want     int
+	}{
+		{"2026-10-01", "2026-10-03", 7},
+		{"2026-10-02", "2026-10-02", 2},
+		{"2026-10-02T15:00:00Z", "2026-10-03T01:00:00Z", 6},
+		{"2026-09-01", "2026-09-30", 0},
+	}
+	for _, tt := range tests {
+		from, _ := parseDateArg(tt.from)
+		to, _ := parseDateArg(tt.to)
+		if got := wikiPageViewsBetween(stats, from, to); got != tt.want {
+			t.Errorf("wikiPageViewsBetween(%s, %s) = %d, want %d", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestWikiPageViewDaysSince(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		from string
+		want int
+	}{
+		{"2026-10-18", 1},
+		{"2026-10-17T23:00:00Z", 2},
+		{"2026-09-19", 30},
+		{"2026-09-18", 31},
+	}
+	for _, tt := range tests {
+		from, _ := parseDateArg(tt.from)
+		if got := wikiPageViewDaysSince(from, now); got != tt.want {
+			t.Errorf("wikiPageViewDaysSince(%s) = %d, want %d", tt.from, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+)
+
+// Matches template variables such as {{title}}
+var templateVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// wikiTemplate is a page skeleton from the wiki's template folder or the local template directory
+type wikiTemplate struct {
+	Name    string
+	Source  string
+	Content string
+}
+
+// templateVariables returns the distinct variables a template uses, in order of appearance
+func templateVariables(content string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, m := range templateVariablePattern.FindAllStringSubmatch(content, -1) {
+		name := strings.ToLower(m[1])
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// renderTemplate substitutes variables, leaving unknown ones in place and returning their names
+func renderTemplate(content string, values map[string]string) (string, []string) {
+	var missing []string
+	rendered := templateVariablePattern.ReplaceAllStringFunc(content, func(match string) string {
+		name := strings.ToLower(templateVariablePattern.FindStringSubmatch(match)[1])
+		if value, ok := values[name]; ok {
+			return value
+		}
+		if !containsString(missing, name) {
+			missing = append(missing, name)
+		}
+		return match
+	})
+	return rendered, missing
+}
+
+// loadWikiTemplates reads the templates under the wiki's template folder and in the local
+// template directory. Local templates replace wiki templates of the same name.
+func loadWikiTemplates(ctx context.Context, w *wiki.WikiV2, versionDescriptor *git.GitVersionDescriptor) ([]wikiTemplate, error) {
+	templates := map[string]wikiTemplate{}
+
+	root := normalizeWikiPath(config.WikiTemplatePath)
+	folder, err := getWikiPageState(ctx, w.Id.String(), root, versionDescriptor)
+	if err != nil {
+		return nil, err
+	}
+	if folder.Exists {
+		pages, err := getWikiPagesWithContent(ctx, w.Id.String(), root, versionDescriptor)
+		if err != nil {
+			return nil, err
+		}
+		for _, page := range pages {
+			if page.Path == nil || *page.Path == root || page.Content == nil {
+				continue
+			}
+			name := strings.TrimPrefix(*page.Path, strings.TrimSuffix(root, "/")+"/")
+			templates[strings.ToLower(name)] = wikiTemplate{Name: name, Source: "wiki page " + *page.Path, Content: *page.Content}
+		}
+	}
+
+	if config.WikiTemplateDir != "" {
+		err := filepath.WalkDir(config.WikiTemplateDir, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || !strings.EqualFold(filepath.Ext(file), ".md") {
+				return nil
+			}
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(config.WikiTemplateDir, file)
+			name := strings.TrimSuffix(filepath.ToSlash(rel), filepath.Ext(rel))
+			templates[strings.ToLower(name)] = wikiTemplate{Name: name, Source: "local file " + rel, Content: string(content)}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read local templates: %v", err)
+		}
+	}
+
+	result := make([]wikiTemplate, 0, len(templates))
+	for _, template := range templates {
+		result = append(result, template)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// parseWorkItemIDs parses a list such as "123, #456 789"
+func parseWorkItemIDs(list string) ([]int, error) {
+	var ids []int
+	for _, field := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ';' || r == ' ' }) {
+		id, err := strconv.Atoi(strings.TrimPrefix(field, "#"))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid work item ID: %s", field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Handler for listing wiki page templates
+func handleListWikiTemplates(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	templates, err := loadWikiTemplates(ctx, selectedWiki, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(templates) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No templates found. Add pages under %s in wiki %s, or set AZURE_DEVOPS_WIKI_TEMPLATE_DIR to a directory of markdown files.",
+			normalizeWikiPath(config.WikiTemplatePath), *selectedWiki.Name)), nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("%d template(s):\n", len(templates)))
+	for _, template := range templates {
+		result.WriteString(fmt.Sprintf("- %s (%s)", template.Name, template.Source))
+		if variables := templateVariables(template.Content); len(variables) > 0 {
+			result.WriteString(" - variables: " + strings.Join(variables, ", "))
+		}
+		result.WriteString("\n")
+	}
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+// Handler for creating a wiki page from a template
+func handleCreateWikiPageFromTemplate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	templateName := strings.TrimSpace(request.Params.Arguments["template"].(string))
+	title := strings.TrimSpace(request.Params.Arguments["title"].(string))
+	parentPath, _ := request.Params.Arguments["parent_path"].(string)
+	workItemList, _ := request.Params.Arguments["work_items"].(string)
+	sprint, hasSprint := request.Params.Arguments["sprint"].(string)
+	team, _ := request.Params.Arguments["team"].(string)
+	author, hasAuthor := request.Params.Arguments["author"].(string)
+	variablesJSON, _ := request.Params.Arguments["variables"].(string)
+	preview, _ := request.Params.Arguments["preview"].(bool)
+
+	if title == "" || strings.Contains(title, "/") {
+		return mcp.NewToolResultError("title must be non-empty and may not contain '/'; use parent_path to place the page"), nil
+	}
+	parent := "/"
+	if parentPath != "" {
+		parent = normalizeWikiPath(parentPath)
+	}
+	pagePath := path.Join(parent, title)
+
+	workItemIDs, err := parseWorkItemIDs(workItemList)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	custom := map[string]string{}
+	if variablesJSON != "" {
+		if err := json.Unmarshal([]byte(variablesJSON), &custom); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid variables JSON, expected an object of strings: %v", err)), nil
+		}
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiWriteVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	templates, err := loadWikiTemplates(ctx, selectedWiki, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var template *wikiTemplate
+	var names []string
+	for i := range templates {
+		names = append(names, templates[i].Name)
+		if strings.EqualFold(templates[i].Name, templateName) {
+			template = &templates[i]
+		}
+	}
+	if template == nil {
+		if len(names) == 0 {
+			return mcp.NewToolResultError("No templates found, see list_wiki_templates"), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Template %s not found, available templates: %s", templateName, strings.Join(names, ", "))), nil
+	}
+
+	now := time.Now()
+	values := map[string]string{
+		"title":    title,
+		"date":     now.Format("2006-01-02"),
+		"datetime": now.Format(time.RFC3339),
+		"year":     now.Format("2006"),
+		"project":  config.Project,
+		"wiki":     *selectedWiki.Name,
+		"parent":   parent,
+		"path":     pagePath,
+	}
+
+	// Values that need a request are only looked up when the template uses them
+	used := templateVariables(template.Content)
+	if containsString(used, "author") {
+		if !hasAuthor || author == "" {
+			if author, err = getAuthenticatedUserName(ctx); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		values["author"] = author
+	}
+	if containsString(used, "sprint") {
+		if !hasSprint || sprint == "" {
+			if sprint, err = getCurrentSprintName(ctx, team); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		values["sprint"] = sprint
+	}
+	var mentions []string
+	for _, id := range workItemIDs {
+		mentions = append(mentions, fmt.Sprintf("#%d", id))
+	}
+	values["work_item_ids"] = strings.Join(mentions, ", ")
+	if containsString(used, "work_items") {
+		titles, err := getWorkItemsAsOf(ctx, workItemIDs, []string{"System.Title"}, nil)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get work items: %v", err)), nil
+		}
+		var lines []string
+		for _, id := range workItemIDs {
+			fields, ok := titles[id]
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("Work item %d not found", id)), nil
+			}
+			lines = append(lines, fmt.Sprintf("- #%d %v", id, fields["System.Title"]))
+		}
+		values["work_items"] = strings.Join(lines, "\n")
+	}
+	for name, value := range custom {
+		values[strings.ToLower(name)] = value
+	}
+
+	content, missing := renderTemplate(template.Content, values)
+	note := ""
+	if len(missing) > 0 {
+		note = fmt.Sprintf("\nUnfilled variables, pass them in variables: %s", strings.Join(missing, ", "))
+	}
+
+	if preview {
+		return mcp.NewToolResultText(fmt.Sprintf("Preview of %s from template %s:%s\n\n%s", pagePath, template.Name, note, content)), nil
+	}
+
+	if parent != "/" {
+		state, err := getWikiPageState(ctx, selectedWiki.Id.String(), parent, versionDescriptor)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if !state.Exists {
+			return mcp.NewToolResultError(fmt.Sprintf("Parent page %s not found", parent)), nil
+		}
+	}
+	existing, err := getWikiPageState(ctx, selectedWiki.Id.String(), pagePath, versionDescriptor)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if existing.Exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Wiki page %s already exists", pagePath)), nil
+	}
+
+	result, err := writeWikiPage(ctx, wikiPageWrite{
+		Wiki:    selectedWiki,
+		Path:    pagePath,
+		Version: versionDescriptor,
+		Content: content,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("%s\nFrom template %s%s", result, template.Name, note)), nil
+}
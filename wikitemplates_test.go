@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	values := map[string]string{"title": "Release 1.2", "date": "2026-01-31", "work_items": "- #1 Fix login"}
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		missing []string
+	}{
+		{"no variables", "Plain text", "Plain text", nil},
+		{"substitutes", "# {{title}}\nDate: {{date}}", "# Release 1.2\nDate: 2026-01-31", nil},
+		{"spaces and case", "{{ Title }} / {{TITLE}}", "Release 1.2 / Release 1.2", nil},
+		{"multi-line values", "Items:\n{{work_items}}", "Items:\n- #1 Fix login", nil},
+		{"unknown kept", "{{owner}} and {{ owner }} on {{date}}", "{{owner}} and {{ owner }} on 2026-01-31", []string{"owner"}},
+		{"not variables", "{{1abc}} {title} {{}}", "{{1abc}} {title} {{}}", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, missing := renderTemplate(tt.content, values)
+			if got != tt.want || !reflect.DeepEqual(missing, tt.missing) {
+				t.Errorf("renderTemplate(%q) = %q, %q, want %q, %q", tt.content, got, missing, tt.want, tt.missing)
+			}
+		})
+	}
+}
+
+func TestTemplateVariables(t *testing.T) {
+	got := templateVariables("{{title}} {{ Date }} {{title}} {{sprint}}")
+	if want := []string{"title", "date", "sprint"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("templateVariables = %q, want %q", got, want)
+	}
+}
+
+func TestParseWorkItemIDs(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"123", []int{123}, false},
+		{"123, #456 789", []int{123, 456, 789}, false},
+		{"1;2,,3", []int{1, 2, 3}, false},
+		{"12a", nil, true},
+		{"0", nil, true},
+		{"-5", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseWorkItemIDs(tt.input)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseWorkItemIDs(%q) = %v, %v, want %v, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
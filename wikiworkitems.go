@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/wiki"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+)
+
+const wikiPageArtifactPrefix = "vstfs:///Wiki/WikiPage/"
+
+// wikiPageLink is a wiki page referenced by an artifact link on a work item
+type wikiPageLink struct {
+	WikiID  string
+	Path    string
+	Comment string
+}
+
+// parseWikiPageArtifactURI extracts the wiki ID and page path from a wiki page artifact URI
+func parseWikiPageArtifactURI(uri string) (string, string, bool) {
+	if len(uri) < len(wikiPageArtifactPrefix) || !strings.EqualFold(uri[:len(wikiPageArtifactPrefix)], wikiPageArtifactPrefix) {
+		return "", "", false
+	}
+	rest, err := url.PathUnescape(uri[len(wikiPageArtifactPrefix):])
+	if err != nil {
+		return "", "", false
+	}
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 3 {
+		return "", "", false
+	}
+	return strings.ToLower(parts[1]), normalizeWikiPath(strings.TrimLeft(parts[2], "/")), true
+}
+
+// wikiPageArtifactURIs returns the forms a link to a page may be stored in: the one
+// buildArtifactURI writes, with the page path's leading slash, and the same without it
+func wikiPageArtifactURIs(ctx context.Context, w *wiki.WikiV2, pagePath string) ([]string, error) {
+	uri, err := buildArtifactURI(ctx, "wiki_page", pagePath, "", w.Id.String())
+	if err != nil {
+		return nil, err
+	}
+	projectID, err := getProjectID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("%s%s%%2F%s%%2F", wikiPageArtifactPrefix, projectID, w.Id.String())
+	encoded := strings.ReplaceAll(url.PathEscape(strings.TrimPrefix(pagePath, "/")), "/", "%2F")
+	return []string{uri, prefix + encoded}, nil
+}
+
+// workItemWikiPageLinks returns the wiki pages a work item links to, with the relation index of each
+func workItemWikiPageLinks(workItem *workitemtracking.WorkItem) map[int]wikiPageLink {
+	links := map[int]wikiPageLink{}
+	if workItem.Relations == nil {
+		return links
+	}
+	for i, relation := range *workItem.Relations {
+		if relation.Rel == nil || relation.Url == nil || *relation.Rel != "ArtifactLink" {
+			continue
+		}
+		wikiID, pagePath, ok := parseWikiPageArtifactURI(*relation.Url)
+		if !ok {
+			continue
+		}
+		link := wikiPageLink{WikiID: wikiID, Path: pagePath}
+		if relation.Attributes != nil {
+			link.Comment, _ = (*relation.Attributes)["comment"].(string)
+		}
+		links[i] = link
+	}
+	return links
+}
+
+// Handler for linking a work item to a wiki page, or removing the link
+func handleLinkWorkItemToWikiPage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := int(request.Params.Arguments["work_item_id"].(float64))
+	pagePath := normalizeWikiPath(request.Params.Arguments["path"].(string))
+	operation, _ := request.Params.Arguments["operation"].(string)
+	if operation == "" {
+		operation = "add"
+	}
+	comment, _ := request.Params.Arguments["comment"].(string)
+	if comment == "" {
+		comment = "Added via MCP"
+	}
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	wikiID := strings.ToLower(selectedWiki.Id.String())
+
+	workItem, err := workItemClient.GetWorkItem(ctx, workitemtracking.GetWorkItemArgs{
+		Id:      &id,
+		Project: &config.Project,
+		Expand:  &workitemtracking.WorkItemExpandValues.Relations,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get work item: %v", err)), nil
+	}
+
+	existing := -1
+	for index, link := range workItemWikiPageLinks(workItem) {
+		if link.WikiID == wikiID && strings.EqualFold(link.Path, pagePath) {
+			existing = index
+			break
+		}
+	}
+
+	var ops []webapi.JsonPatchOperation
+	switch operation {
+	case "add":
+		if existing != -1 {
+			return mcp.NewToolResultText(fmt.Sprintf("Work item #%d is already linked to wiki page %s", id, pagePath)), nil
+		}
+		versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		state, err := getWikiPageState(ctx, selectedWiki.Id.String(), pagePath, versionDescriptor)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if !state.Exists {
+			return mcp.NewToolResultError(fmt.Sprintf("Wiki page %s not found", pagePath)), nil
+		}
+		targetURL, err := buildArtifactURI(ctx, "wiki_page", pagePath, "", selectedWiki.Id.String())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		ops = []webapi.JsonPatchOperation{
+			{
+				Op:   &webapi.OperationValues.Add,
+				Path: stringPtr("/relations/-"),
+				Value: map[string]interface{}{
+					"rel": "ArtifactLink",
+					"url": targetURL,
+					"attributes": map[string]interface{}{
+						"name":    artifactLinkNames["wiki_page"],
+						"comment": comment,
+					},
+				},
+			},
+		}
+	case "remove":
+		if existing == -1 {
+			return mcp.NewToolResultError(fmt.Sprintf("Work item #%d is not linked to wiki page %s", id, pagePath)), nil
+		}
+		ops = []webapi.JsonPatchOperation{
+			{
+				Op:   &webapi.OperationValues.Remove,
+				Path: stringPtr(fmt.Sprintf("/relations/%d", existing)),
+			},
+		}
+	default:
+		return mcp.NewToolResultError(fmt.Sprintf("Unknown operation %s, use add or remove", operation)), nil
+	}
+
+	_, err = workItemClient.UpdateWorkItem(ctx, workitemtracking.UpdateWorkItemArgs{
+		Id:       &id,
+		Project:  &config.Project,
+		Document: &ops,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to update work item relations: %v", err)), nil
+	}
+
+	if operation == "add" {
+		return mcp.NewToolResultText(fmt.Sprintf("Linked work item #%d to wiki page %s in wiki %s", id, pagePath, *selectedWiki.Name)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Removed the link from work item #%d to wiki page %s in wiki %s", id, pagePath, *selectedWiki.Name)), nil
+}
+
+// Handler for listing the work items linked to a wiki page
+func handleGetWikiPageWorkItems(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	rootPath := normalizeWikiPath(request.Params.Arguments["path"].(string))
+	includeSubpages, _ := request.Params.Arguments["include_subpages"].(bool)
+
+	selectedWiki, err := resolveWiki(ctx, wikiArg(request))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	pagePaths := []string{rootPath}
+	if includeSubpages {
+		tree, err := getWikiPageTree(ctx, selectedWiki.Id.String(), rootPath, versionDescriptor)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		pagePaths = nil
+		for _, page := range flattenWikiPages(*tree) {
+			if page.Path != nil {
+				pagePaths = append(pagePaths, *page.Path)
+			}
+		}
+	}
+
+	// The query matches URIs exactly, so ask for every form a link may be stored in
+	uriPages := map[string]string{}
+	var uris []string
+	for _, pagePath := range pagePaths {
+		forms, err := wikiPageArtifactURIs(ctx, selectedWiki, pagePath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		for _, uri := range forms {
+			uriPages[strings.ToLower(uri)] = pagePath
+			uris = append(uris, uri)
+		}
+	}
+
+	linked := map[string][]int{}
+	for start := 0; start < len(uris); start += 100 {
+		batch := uris[start:min(start+100, len(uris))]
+		result, err := workItemClient.QueryWorkItemsForArtifactUris(ctx, workitemtracking.QueryWorkItemsForArtifactUrisArgs{
+			ArtifactUriQuery: &workitemtracking.ArtifactUriQuery{ArtifactUris: &batch},
+			Project:          &config.Project,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query linked work items: %v", err)), nil
+		}
+		if result.ArtifactUrisQueryResult == nil {
+			continue
+		}
+		for uri, references := range *result.ArtifactUrisQueryResult {
+			pagePath := uriPages[strings.ToLower(uri)]
+			for _, reference := range references {
+				if reference.Id != nil && !containsInt(linked[pagePath], *reference.Id) {
+					linked[pagePath] = append(linked[pagePath], *reference.Id)
+				}
+			}
+		}
+	}
+
+	var ids []int
+	for _, pageIDs := range linked {
+		for _, id := range pageIDs {
+			if !containsInt(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No work items are linked to wiki page %s", rootPath)), nil
+	}
+	items, err := getWorkItemsAsOf(ctx, ids, []string{"System.Title", "System.WorkItemType", "System.State"}, nil)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get work items: %v", err)), nil
+	}
+
+	var result strings.Builder
+	for _, pagePath := range pagePaths {
+		pageIDs := linked[pagePath]
+		if len(pageIDs) == 0 {
+			continue
+		}
+		sort.Ints(pageIDs)
+		result.WriteString(fmt.Sprintf("Work items linked to %s:\n", pagePath))
+		for _, id := range pageIDs {
+			fields, ok := items[id]
+			if !ok {
+				result.WriteString(fmt.Sprintf("- #%d (not accessible)\n", id))
+				continue
+			}
+			result.WriteString(fmt.Sprintf("- #%d [%v] %v (%v)\n", id, fields["System.WorkItemType"], fields["System.Title"], fields["System.State"]))
+		}
+		result.WriteString("\n")
+	}
+	return mcp.NewToolResultText(strings.TrimSpace(result.String())), nil
+}
+
+// Handler for listing the wiki pages a work item links to
+func handleGetWorkItemWikiPages(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := int(request.Params.Arguments["work_item_id"].(float64))
+	includeContent, _ := request.Params.Arguments["include_content"].(bool)
+
+	workItem, err := workItemClient.GetWorkItem(ctx, workitemtracking.GetWorkItemArgs{
+		Id:      &id,
+		Project: &config.Project,
+		Expand:  &workitemtracking.WorkItemExpandValues.Relations,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get work item: %v", err)), nil
+	}
+
+	links := workItemWikiPageLinks(workItem)
+	if len(links) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Work item #%d is not linked to any wiki pages", id)), nil
+	}
+	indexes := make([]int, 0, len(links))
+	for index := range links {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Wiki pages linked from work item #%d:\n", id))
+	var contents []string
+	for _, index := range indexes {
+		link := links[index]
+		selectedWiki, err := resolveWiki(ctx, link.WikiID)
+		if err != nil {
+			result.WriteString(fmt.Sprintf("- %s (wiki %s not found)\n", link.Path, link.WikiID))
+			continue
+		}
+		line := fmt.Sprintf("- %s (wiki %s)", link.Path, *selectedWiki.Name)
+		if link.Comment != "" {
+			line += " - " + link.Comment
+		}
+
+		if includeContent {
+			versionDescriptor, err := wikiVersionArg(request, selectedWiki)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			state, err := getWikiPageState(ctx, selectedWiki.Id.String(), link.Path, versionDescriptor)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if !state.Exists {
+				line += " (page not found, it may have been moved or deleted)"
+			} else {
+				contents = append(contents, fmt.Sprintf("--- %s ---\n%s", link.Path, state.Content))
+			}
+		}
+		result.WriteString(line + "\n")
+	}
+	if len(contents) > 0 {
+		result.WriteString("\n" + strings.Join(contents, "\n\n"))
+	}
+	return mcp.NewToolResultText(result.String()), nil
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestParseWikiPageArtifactURI(t *testing.T) {
+	const project = "6ce954b1-ce1f-45d1-b94d-e6bf2464ba2c"
+	const wikiID = "A2D0F1B4-5D1E-4F6A-9E0B-3C2A1D0E9F8B"
+	tests := []struct {
+		name   string
+		uri    string
+		wiki   string
+		page   string
+		wantOK bool
+	}{
+		{"leading slash", "vstfs:///Wiki/WikiPage/" + project + "%2F" + wikiID + "%2F%2FTeam%2FGetting%20Started", "a2d0f1b4-5d1e-4f6a-9e0b-3c2a1d0e9f8b", "/Team/Getting Started", true},
+		{"no leading slash", "vstfs:///Wiki/WikiPage/" + project + "%2F" + wikiID + "%2FTeam%2FGuide", "a2d0f1b4-5d1e-4f6a-9e0b-3c2a1d0e9f8b", "/Team/Guide", true},
+		{"prefix case", "VSTFS:///wiki/wikipage/" + project + "%2F" + wikiID + "%2FHome", "a2d0f1b4-5d1e-4f6a-9e0b-3c2a1d0e9f8b", "/Home", true},
+		{"other artifact", "vstfs:///Git/Commit/" + project + "%2Frepo%2Fabc", "", "", false},
+		{"missing page", "vstfs:///Wiki/WikiPage/" + project + "%2F" + wikiID, "", "", false},
+		{"bad escape", "vstfs:///Wiki/WikiPage/" + project + "%2F" + wikiID + "%2F%zz", "", "", false},
+		{"too short", "vstfs:///Wiki", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wiki, page, ok := parseWikiPageArtifactURI(tt.uri)
+			if ok != tt.wantOK || wiki != tt.wiki || page != tt.page {
+				t.Errorf("parseWikiPageArtifactURI(%q) = %q, %q, %v, want %q, %q, %v", tt.uri, wiki, page, ok, tt.wiki, tt.page, tt.wantOK)
+			}
+		})
+	}
+}
@@ -40,12 +40,19 @@ func addWorkItemTools(s *server.MCPServer) {
 		),
 		mcp.WithString("description",
 			mcp.Required(),
-			mcp.Description("Description of the work item"),
+			mcp.Description("Description of the work item (markdown supported)"),
 		),
 		mcp.WithString("priority",
 			mcp.Description("Priority of the work item (1-4)"),
 			mcp.Enum("1", "2", "3", "4"),
 		),
+		mcp.WithString("assigned_to",
+			mcp.Description("User to assign the work item to (name, partial name or email)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Format of rich text fields such as the description (markdown is converted to HTML, defaults to markdown)"),
+			mcp.Enum("markdown", "html"),
+		),
 	)
 
 	s.AddTool(createWorkItemTool, handleCreateWorkItem)
@@ -59,12 +66,16 @@ func addWorkItemTools(s *server.MCPServer) {
 		),
 		mcp.WithString("field",
 			mcp.Required(),
-			mcp.Description("Field to update (Title, Description, State, Priority)"),
-			mcp.Enum("Title", "Description", "State", "Priority"),
+			mcp.Description("Field to update (Title, Description, State, Priority, AssignedTo, ReproSteps, AcceptanceCriteria)"),
+			mcp.Enum("Title", "Description", "State", "Priority", "AssignedTo", "ReproSteps", "AcceptanceCriteria"),
 		),
 		mcp.WithString("value",
 			mcp.Required(),
-			mcp.Description("New value for the field"),
+			mcp.Description("New value for the field (for AssignedTo, a name, partial name or email; rich text fields accept markdown)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Format of rich text fields such as the description (markdown is converted to HTML, defaults to markdown)"),
+			mcp.Enum("markdown", "html"),
 		),
 	)
 
@@ -77,6 +88,9 @@ func addWorkItemTools(s *server.MCPServer) {
 			mcp.Required(),
 			mcp.Description("WIQL query string"),
 		),
+		mcp.WithString("as_of",
+			mcp.Description("Optional point in time to run the query at (YYYY-MM-DD or RFC3339)"),
+		),
 	)
 
 	s.AddTool(queryWorkItemsTool, handleQueryWorkItems)
@@ -88,33 +102,86 @@ func addWorkItemTools(s *server.MCPServer) {
 			mcp.Required(),
 			mcp.Description("Comma-separated list of work item IDs"),
 		),
+		mcp.WithString("as_of",
+			mcp.Description("Optional point in time to read the work items at (YYYY-MM-DD or RFC3339)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Format to return rich text fields in (defaults to markdown)"),
+			mcp.Enum("markdown", "html"),
+		),
+		mcp.WithBoolean("include_images",
+			mcp.Description("Also return images embedded in the description and repro steps as image content"),
+		),
 	)
 	s.AddTool(getWorkItemTool, handleGetWorkItemDetails)
 
+	// Compare Query Snapshots
+	compareSnapshotsTool := mcp.NewTool("compare_snapshots",
+		mcp.WithDescription("Compare the results of a WIQL query between two points in time, showing scope added, removed and changed"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("WIQL query string"),
+		),
+		mcp.WithString("from",
+			mcp.Required(),
+			mcp.Description("Earlier point in time (YYYY-MM-DD or RFC3339), e.g. the sprint start"),
+		),
+		mcp.WithString("to",
+			mcp.Description("Later point in time (YYYY-MM-DD or RFC3339, defaults to now)"),
+		),
+		mcp.WithString("fields",
+			mcp.Description("Optional comma-separated field reference names to compare (defaults to title, state, assignee, iteration and estimates)"),
+		),
+	)
+	s.AddTool(compareSnapshotsTool, handleCompareSnapshots)
+
 	// Manage Work Item Relations
 	manageRelationsTool := mcp.NewTool("manage_work_item_relations",
-		mcp.WithDescription("Manage relationships between work items"),
+		mcp.WithDescription("Manage links from a work item to other work items, hyperlinks and artifacts (commits, pull requests, builds, wiki pages)"),
 		mcp.WithNumber("source_id",
 			mcp.Required(),
 			mcp.Description("ID of the source work item"),
 		),
-		mcp.WithNumber("target_id",
-			mcp.Required(),
-			mcp.Description("ID of the target work item"),
-		),
 		mcp.WithString("relation_type",
 			mcp.Required(),
-			mcp.Description("Type of relationship to manage"),
-			mcp.Enum("parent", "child", "related"),
+			mcp.Description("Type of link: parent, child, related, predecessor, successor, duplicate, duplicate-of, tested-by, tests, affects, affected-by, hyperlink, artifact, or any link type name or reference name from list_link_types"),
 		),
 		mcp.WithString("operation",
 			mcp.Required(),
 			mcp.Description("Operation to perform"),
 			mcp.Enum("add", "remove"),
 		),
+		mcp.WithNumber("target_id",
+			mcp.Description("ID of the target work item (for work item links)"),
+		),
+		mcp.WithString("url",
+			mcp.Description("Target URL (for hyperlinks)"),
+		),
+		mcp.WithString("artifact_type",
+			mcp.Description("Kind of artifact to link (for artifact links)"),
+			mcp.Enum("commit", "pull_request", "build", "wiki_page"),
+		),
+		mcp.WithString("artifact_id",
+			mcp.Description("Commit SHA, pull request ID, build ID or wiki page path (for artifact links)"),
+		),
+		mcp.WithString("repository",
+			mcp.Description("Repository name or ID (for commit and pull request links)"),
+		),
+		mcp.WithString("wiki",
+			mcp.Description("Wiki name or ID (for wiki page links, defaults to the first wiki)"),
+		),
+		mcp.WithString("comment",
+			mcp.Description("Optional comment to store on the link"),
+		),
 	)
 	s.AddTool(manageRelationsTool, handleManageWorkItemRelations)
 
+	// List Link Types
+	listLinkTypesTool := mcp.NewTool("list_link_types",
+		mcp.WithDescription("List the work item and resource link types available in the organization"),
+	)
+	s.AddTool(listLinkTypesTool, handleListLinkTypes)
+
 	// Get Related Work Items
 	getRelatedItemsTool := mcp.NewTool("get_related_work_items",
 		mcp.WithDescription("Get related work items"),
@@ -130,26 +197,132 @@ func addWorkItemTools(s *server.MCPServer) {
 	)
 	s.AddTool(getRelatedItemsTool, handleGetRelatedWorkItems)
 
-	// Comment Management Tool (as Discussion)
+	// Work Item History
+	getHistoryTool := mcp.NewTool("get_work_item_history",
+		mcp.WithDescription("Get the chronological change history of a work item with per-field old and new values, relation changes and comments"),
+		mcp.WithNumber("id",
+			mcp.Required(),
+			mcp.Description("ID of the work item"),
+		),
+		mcp.WithString("fields",
+			mcp.Description("Optional comma-separated field names to filter on (case-insensitive partial match, use 'relations' for link changes)"),
+		),
+		mcp.WithString("since",
+			mcp.Description("Only include changes on or after this date (YYYY-MM-DD or RFC3339)"),
+		),
+		mcp.WithString("until",
+			mcp.Description("Only include changes on or before this date (YYYY-MM-DD or RFC3339)"),
+		),
+	)
+	s.AddTool(getHistoryTool, handleGetWorkItemHistory)
+
+	// Identity Search
+	searchIdentitiesTool := mcp.NewTool("search_identities",
+		mcp.WithDescription("Search users and groups by partial name or email, e.g. to find who to assign work to"),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Partial name or email to search for"),
+		),
+	)
+	s.AddTool(searchIdentitiesTool, handleSearchIdentities)
+
+	// Comment Management Tools
 	addCommentTool := mcp.NewTool("add_work_item_comment",
-		mcp.WithDescription("Add a comment to a work item as a discussion"),
+		mcp.WithDescription("Add a comment to a work item's discussion"),
 		mcp.WithNumber("id",
 			mcp.Required(),
 			mcp.Description("ID of the work item"),
 		),
 		mcp.WithString("text",
 			mcp.Required(),
-			mcp.Description("Comment text"),
+			mcp.Description("Comment text (@name or @email mentions are converted to Azure DevOps mentions)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Format of the comment text (defaults to markdown)"),
+			mcp.Enum("markdown", "html"),
 		),
 	)
 	s.AddTool(addCommentTool, handleAddWorkItemComment)
 
+	updateCommentTool := mcp.NewTool("update_work_item_comment",
+		mcp.WithDescription("Edit an existing work item comment"),
+		mcp.WithNumber("id",
+			mcp.Required(),
+			mcp.Description("ID of the work item"),
+		),
+		mcp.WithNumber("comment_id",
+			mcp.Required(),
+			mcp.Description("ID of the comment to edit"),
+		),
+		mcp.WithString("text",
+			mcp.Required(),
+			mcp.Description("New comment text (@name or @email mentions are converted to Azure DevOps mentions)"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Format of the comment text (defaults to markdown)"),
+			mcp.Enum("markdown", "html"),
+		),
+	)
+	s.AddTool(updateCommentTool, handleUpdateWorkItemComment)
+
+	deleteCommentTool := mcp.NewTool("delete_work_item_comment",
+		mcp.WithDescription("Delete a work item comment"),
+		mcp.WithNumber("id",
+			mcp.Required(),
+			mcp.Description("ID of the work item"),
+		),
+		mcp.WithNumber("comment_id",
+			mcp.Required(),
+			mcp.Description("ID of the comment to delete"),
+		),
+	)
+	s.AddTool(deleteCommentTool, handleDeleteWorkItemComment)
+
+	commentReactionTool := mcp.NewTool("manage_comment_reaction",
+		mcp.WithDescription("Add or remove a reaction on a work item comment"),
+		mcp.WithNumber("id",
+			mcp.Required(),
+			mcp.Description("ID of the work item"),
+		),
+		mcp.WithNumber("comment_id",
+			mcp.Required(),
+			mcp.Description("ID of the comment"),
+		),
+		mcp.WithString("reaction",
+			mcp.Required(),
+			mcp.Description("Reaction type"),
+			mcp.Enum("like", "dislike", "heart", "hooray", "smile", "confused"),
+		),
+		mcp.WithString("operation",
+			mcp.Required(),
+			mcp.Description("Operation to perform"),
+			mcp.Enum("add", "remove"),
+		),
+	)
+	s.AddTool(commentReactionTool, handleManageCommentReaction)
+
 	getCommentsTool := mcp.NewTool("get_work_item_comments",
-		mcp.WithDescription("Get comments for a work item"),
+		mcp.WithDescription("Get comments for a work item, one page at a time"),
 		mcp.WithNumber("id",
 			mcp.Required(),
 			mcp.Description("ID of the work item"),
 		),
+		mcp.WithNumber("page_size",
+			mcp.Description("Maximum number of comments to return (defaults to 50)"),
+		),
+		mcp.WithString("continuation_token",
+			mcp.Description("Token from a previous call to fetch the next page"),
+		),
+		mcp.WithString("order",
+			mcp.Description("Sort order by creation date (defaults to asc)"),
+			mcp.Enum("asc", "desc"),
+		),
+		mcp.WithBoolean("include_deleted",
+			mcp.Description("Whether to include deleted comments"),
+		),
+		mcp.WithBoolean("include_images",
+			mcp.Description("Also return images embedded in the comments as image content"),
+		),
 	)
 	s.AddTool(getCommentsTool, handleGetWorkItemComments)
 
@@ -171,7 +344,7 @@ func addWorkItemTools(s *server.MCPServer) {
 		mcp.WithDescription("Create multiple work items in a single operation"),
 		mcp.WithString("items",
 			mcp.Required(),
-			mcp.Description("JSON array of work items to create, each containing type, title, and description"),
+			mcp.Description("JSON array of work items to create, each containing type, title, description, and optionally priority and assigned_to"),
 		),
 	)
 	s.AddTool(batchCreateTool, handleBatchCreateWorkItems)
@@ -245,16 +418,53 @@ func addWorkItemTools(s *server.MCPServer) {
 			mcp.Description("ID of the work item"),
 		),
 		mcp.WithString("file_name",
-			mcp.Required(),
-			mcp.Description("Name of the file to attach"),
+			mcp.Description("Name of the file to attach (defaults to the name of file_path)"),
 		),
 		mcp.WithString("content",
-			mcp.Required(),
-			mcp.Description("Base64 encoded content of the file"),
+			mcp.Description("Base64 encoded content of the file (use file_path for large files)"),
+		),
+		mcp.WithString("file_path",
+			mcp.Description("Path of a local file inside the configured upload directory"),
+		),
+		mcp.WithString("comment",
+			mcp.Description("Optional comment to store on the attachment"),
 		),
 	)
 	s.AddTool(addAttachmentTool, handleAddWorkItemAttachment)
 
+	addInlineImageTool := mcp.NewTool("add_inline_image",
+		mcp.WithDescription("Upload an image and embed it in a work item's description, repro steps or a new comment"),
+		mcp.WithNumber("id",
+			mcp.Required(),
+			mcp.Description("ID of the work item"),
+		),
+		mcp.WithString("target",
+			mcp.Required(),
+			mcp.Description("Where to embed the image"),
+			mcp.Enum("description", "repro_steps", "comment"),
+		),
+		mcp.WithString("file_path",
+			mcp.Description("Path of a local image inside the configured upload directory"),
+		),
+		mcp.WithString("content",
+			mcp.Description("Base64 encoded image content"),
+		),
+		mcp.WithString("file_name",
+			mcp.Description("Name of the image file (defaults to the name of file_path)"),
+		),
+		mcp.WithString("alt_text",
+			mcp.Description("Alternative text for the image (defaults to the file name)"),
+		),
+		mcp.WithString("text",
+			mcp.Description("Optional markdown text placed before the image, e.g. the comment body"),
+		),
+		mcp.WithString("position",
+			mcp.Description("Whether to add the image at the end or the start of the field (defaults to append)"),
+			mcp.Enum("append", "prepend"),
+		),
+	)
+	s.AddTool(addInlineImageTool, handleAddInlineImage)
+
 	getAttachmentsTool := mcp.NewTool("get_work_item_attachments",
 		mcp.WithDescription("Get attachments for a work item"),
 		mcp.WithNumber("id",
@@ -264,6 +474,24 @@ func addWorkItemTools(s *server.MCPServer) {
 	)
 	s.AddTool(getAttachmentsTool, handleGetWorkItemAttachments)
 
+	getAttachmentContentTool := mcp.NewTool("get_attachment_content",
+		mcp.WithDescription("Download an attachment: text files are returned inline, images as image content, other files as base64 or saved to the download directory"),
+		mcp.WithString("attachment",
+			mcp.Required(),
+			mcp.Description("Attachment GUID, attachment URL, or file name (file name requires id)"),
+		),
+		mcp.WithNumber("id",
+			mcp.Description("ID of the work item the attachment belongs to"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description("Maximum number of bytes of text to return inline (defaults to 102400)"),
+		),
+		mcp.WithBoolean("save",
+			mcp.Description("Save the file to the configured download directory instead of returning it"),
+		),
+	)
+	s.AddTool(getAttachmentContentTool, handleGetAttachmentContent)
+
 	removeAttachmentTool := mcp.NewTool("remove_work_item_attachment",
 		mcp.WithDescription("Remove an attachment from a work item"),
 		mcp.WithNumber("id",
@@ -271,8 +499,16 @@ func addWorkItemTools(s *server.MCPServer) {
 			mcp.Description("ID of the work item"),
 		),
 		mcp.WithString("attachment_id",
-			mcp.Required(),
-			mcp.Description("ID of the attachment to remove"),
+			mcp.Description("GUID or exact file name of the attachment to remove"),
+		),
+		mcp.WithString("name_pattern",
+			mcp.Description("Bulk removal: remove attachments whose file name matches this glob pattern (e.g. *.log)"),
+		),
+		mcp.WithString("older_than",
+			mcp.Description("Bulk removal: remove attachments added before this date (YYYY-MM-DD or RFC3339)"),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("Must be true to carry out a bulk removal; otherwise the matching attachments are only listed"),
 		),
 	)
 	s.AddTool(removeAttachmentTool, handleRemoveWorkItemAttachment)
@@ -281,7 +517,7 @@ func addWorkItemTools(s *server.MCPServer) {
 	getCurrentSprintTool := mcp.NewTool("get_current_sprint",
 		mcp.WithDescription("Get details about the current sprint"),
 		mcp.WithString("team",
-			mcp.Description("Team name (optional, defaults to project's default team)"),
+			mcp.Description("Team name (optional, defaults to AZURE_DEVOPS_TEAM or the project's default team)"),
 		),
 	)
 	s.AddTool(getCurrentSprintTool, handleGetCurrentSprint)
@@ -289,7 +525,7 @@ func addWorkItemTools(s *server.MCPServer) {
 	getSprintsTool := mcp.NewTool("get_sprints",
 		mcp.WithDescription("Get list of sprints"),
 		mcp.WithString("team",
-			mcp.Description("Team name (optional, defaults to project's default team)"),
+			mcp.Description("Team name (optional, defaults to AZURE_DEVOPS_TEAM or the project's default team)"),
 		),
 		mcp.WithBoolean("include_completed",
 			mcp.Description("Whether to include completed sprints"),
@@ -297,6 +533,14 @@ func addWorkItemTools(s *server.MCPServer) {
 	)
 	s.AddTool(getSprintsTool, handleGetSprints)
 
+	listTeamsTool := mcp.NewTool("list_teams",
+		mcp.WithDescription("List the teams of the project, marking the default team"),
+		mcp.WithBoolean("mine",
+			mcp.Description("Only list teams you are a member of"),
+		),
+	)
+	s.AddTool(listTeamsTool, handleListTeams)
+
 	// Add a new prompt for work item descriptions
 	s.AddPrompt(mcp.NewPrompt("format_work_item_description",
 		mcp.WithPromptDescription("Format a work item description using proper HTML for Azure DevOps"),
@@ -312,6 +556,24 @@ func handleUpdateWorkItem(ctx context.Context, request mcp.CallToolRequest) (*mc
 	field := request.Params.Arguments["field"].(string)
 	value := request.Params.Arguments["value"].(string)
 
+	format, _ := request.Params.Arguments["format"].(string)
+
+	if isAssignedToField(field) {
+		assignedTo, err := resolveAssignedTo(ctx, value)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		value = assignedTo
+		field = "System.AssignedTo"
+	}
+
+	// Rich text fields are stored as HTML
+	var fieldValue interface{} = value
+	if reference := htmlFieldReference(field); reference != "" {
+		field = reference
+		fieldValue = convertFieldValue(field, value, format)
+	}
+
 	// Instead of using a fixed map, directly use the field name
 	// This allows any valid Azure DevOps field to be used
 	updateArgs := workitemtracking.UpdateWorkItemArgs{
@@ -321,7 +583,7 @@ func handleUpdateWorkItem(ctx context.Context, request mcp.CallToolRequest) (*mc
 			{
 				Op:    &webapi.OperationValues.Replace,
 				Path:  stringPtr("/fields/" + field),
-				Value: value,
+				Value: fieldValue,
 			},
 		},
 	}
@@ -339,6 +601,8 @@ func handleCreateWorkItem(ctx context.Context, request mcp.CallToolRequest) (*mc
 	title := request.Params.Arguments["title"].(string)
 	description := request.Params.Arguments["description"].(string)
 	priority, hasPriority := request.Params.Arguments["priority"].(string)
+	assignedTo, _ := request.Params.Arguments["assigned_to"].(string)
+	format, _ := request.Params.Arguments["format"].(string)
 
 	// Create the work item
 	createArgs := workitemtracking.CreateWorkItemArgs{
@@ -353,7 +617,7 @@ func handleCreateWorkItem(ctx context.Context, request mcp.CallToolRequest) (*mc
 			{
 				Op:    &webapi.OperationValues.Add,
 				Path:  stringPtr("/fields/System.Description"),
-				Value: description,
+				Value: convertFieldValue("System.Description", description, format),
 			},
 		},
 	}
@@ -367,6 +631,19 @@ func handleCreateWorkItem(ctx context.Context, request mcp.CallToolRequest) (*mc
 		createArgs.Document = &doc
 	}
 
+	if assignedTo != "" {
+		resolved, err := resolveAssignedTo(ctx, assignedTo)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		doc := append(*createArgs.Document, webapi.JsonPatchOperation{
+			Op:    &webapi.OperationValues.Add,
+			Path:  stringPtr("/fields/System.AssignedTo"),
+			Value: resolved,
+		})
+		createArgs.Document = &doc
+	}
+
 	workItem, err := workItemClient.CreateWorkItem(ctx, createArgs)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create work item: %v", err)), nil
@@ -382,6 +659,13 @@ func handleCreateWorkItem(ctx context.Context, request mcp.CallToolRequest) (*mc
 
 func handleQueryWorkItems(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	query := request.Params.Arguments["query"].(string)
+	asOf, err := parseAsOfArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if asOf != nil {
+		query = applyAsOfClause(query, asOf.Time)
+	}
 
 	// Create WIQL query
 	wiqlArgs := workitemtracking.QueryByWiqlArgs{
@@ -431,7 +715,8 @@ func handleQueryWorkItems(ctx context.Context, request mcp.CallToolRequest) (*mc
 			
 			// Fetch details for these work items
 			getArgs := workitemtracking.GetWorkItemsArgs{
-				Ids: &ids,
+				Ids:  &ids,
+				AsOf: asOf,
 			}
 			workItems, err := workItemClient.GetWorkItems(ctx, getArgs)
 			if err == nil && workItems != nil && len(*workItems) > 0 {
@@ -526,12 +811,11 @@ func handleFormatWorkItemDescription(ctx context.Context, request mcp.GetPromptR
 		[]mcp.PromptMessage{
 			mcp.NewPromptMessage(
 				"system",
-				mcp.NewTextContent("You format work item descriptions for Azure DevOps. Use proper HTML formatting with <ul>, <li> for bullet points, <p> for paragraphs, and <br> for line breaks."),
+				mcp.NewTextContent("You format work item descriptions for Azure DevOps. Descriptions are stored as HTML; write markdown (lists, tables, code blocks, links, checkboxes) and it is converted to HTML."),
 			),
 			mcp.NewPromptMessage(
 				"assistant",
-				mcp.NewTextContent(fmt.Sprintf("Here's your description formatted with HTML:\n\n<ul>\n%s\n</ul>",
-					strings.Join(strings.Split(description, "-"), "</li>\n<li>"))),
+				mcp.NewTextContent(fmt.Sprintf("Here's your description formatted with HTML:\n\n%s", markdownToHTML(description))),
 			),
 		},
 	), nil
@@ -551,26 +835,56 @@ func handleGetWorkItemDetails(ctx context.Context, request mcp.CallToolRequest)
 		ids = append(ids, id)
 	}
 
+	asOf, err := parseAsOfArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	workItems, err := workItemClient.GetWorkItems(ctx, workitemtracking.GetWorkItemsArgs{
 		Ids:     &ids,
 		Project: &config.Project,
 		Expand:  &workitemtracking.WorkItemExpandValues.All,
+		AsOf:    asOf,
 	})
 
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get work items: %v", err)), nil
 	}
 
+	format, _ := request.Params.Arguments["format"].(string)
+	richText := func(value string) string {
+		if format == "html" {
+			return value
+		}
+		return htmlToMarkdown(value)
+	}
+
+	includeImages, _ := request.Params.Arguments["include_images"].(bool)
+	var imageFields []string
+
 	var results []string
 	for _, item := range *workItems {
 		fields := *item.Fields
 		title, _ := fields["System.Title"].(string)
 		description, _ := fields["System.Description"].(string)
 		state, _ := fields["System.State"].(string)
+		imageFields = append(imageFields, description)
 
-		result := fmt.Sprintf("ID: %d\nTitle: %s\nState: %s\nDescription: %s\n---\n",
-			*item.Id, title, state, description)
-		results = append(results, result)
+		result := fmt.Sprintf("ID: %d\nTitle: %s\nState: %s\nDescription: %s\n",
+			*item.Id, title, state, richText(description))
+		if reproSteps, ok := fields["Microsoft.VSTS.TCM.ReproSteps"].(string); ok && reproSteps != "" {
+			result += fmt.Sprintf("Repro Steps: %s\n", richText(reproSteps))
+			imageFields = append(imageFields, reproSteps)
+		}
+		if criteria, ok := fields["Microsoft.VSTS.Common.AcceptanceCriteria"].(string); ok && criteria != "" {
+			result += fmt.Sprintf("Acceptance Criteria: %s\n", richText(criteria))
+		}
+		results = append(results, result+"---\n")
+	}
+
+	if includeImages {
+		images, notes := embeddedImages(ctx, imageFields...)
+		return textWithImages(strings.Join(results, "\n"), images, notes), nil
 	}
 
 	return mcp.NewToolResultText(strings.Join(results, "\n")), nil
@@ -579,21 +893,49 @@ func handleGetWorkItemDetails(ctx context.Context, request mcp.CallToolRequest)
 // Handler for managing work item relationships
 func handleManageWorkItemRelations(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	sourceID := int(request.Params.Arguments["source_id"].(float64))
-	targetID := int(request.Params.Arguments["target_id"].(float64))
 	relationType, ok := request.Params.Arguments["relation_type"].(string)
 	if !ok {
 		return mcp.NewToolResultError("Invalid relation_type"), nil
 	}
 	operation := request.Params.Arguments["operation"].(string)
+	comment, _ := request.Params.Arguments["comment"].(string)
+	if comment == "" {
+		comment = "Added via MCP"
+	}
 
-	// Map relation types to Azure DevOps relation types
-	relationTypeMap := map[string]string{
-		"parent":  "System.LinkTypes.Hierarchy-Reverse",
-		"child":   "System.LinkTypes.Hierarchy-Forward",
-		"related": "System.LinkTypes.Related",
+	azureRelationType, err := resolveRelationType(ctx, relationType)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	azureRelationType := relationTypeMap[relationType]
+	// Work out what the link points at
+	var targetURL string
+	attributes := map[string]interface{}{
+		"comment": comment,
+	}
+	switch azureRelationType {
+	case "Hyperlink":
+		targetURL, _ = request.Params.Arguments["url"].(string)
+		if targetURL == "" {
+			return mcp.NewToolResultError("url is required for hyperlinks"), nil
+		}
+	case "ArtifactLink":
+		artifactType, _ := request.Params.Arguments["artifact_type"].(string)
+		artifactID, _ := request.Params.Arguments["artifact_id"].(string)
+		repository, _ := request.Params.Arguments["repository"].(string)
+		wikiName, _ := request.Params.Arguments["wiki"].(string)
+		targetURL, err = buildArtifactURI(ctx, artifactType, artifactID, repository, wikiName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		attributes["name"] = artifactLinkNames[artifactType]
+	default:
+		targetID, ok := request.Params.Arguments["target_id"].(float64)
+		if !ok {
+			return mcp.NewToolResultError("target_id is required for work item links"), nil
+		}
+		targetURL = fmt.Sprintf("%s/_apis/wit/workItems/%d", config.OrganizationURL, int(targetID))
+	}
 
 	var ops []webapi.JsonPatchOperation
 	if operation == "add" {
@@ -602,11 +944,9 @@ func handleManageWorkItemRelations(ctx context.Context, request mcp.CallToolRequ
 				Op:   &webapi.OperationValues.Add,
 				Path: stringPtr("/relations/-"),
 				Value: map[string]interface{}{
-					"rel": azureRelationType,
-					"url": fmt.Sprintf("%s/_apis/wit/workItems/%d", config.OrganizationURL, targetID),
-					"attributes": map[string]interface{}{
-						"comment": "Added via MCP",
-					},
+					"rel":        azureRelationType,
+					"url":        targetURL,
+					"attributes": attributes,
 				},
 			},
 		}
@@ -615,6 +955,7 @@ func handleManageWorkItemRelations(ctx context.Context, request mcp.CallToolRequ
 		workItem, err := workItemClient.GetWorkItem(ctx, workitemtracking.GetWorkItemArgs{
 			Id:      &sourceID,
 			Project: &config.Project,
+			Expand:  &workitemtracking.WorkItemExpandValues.Relations,
 		})
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to get work item: %v", err)), nil
@@ -625,17 +966,17 @@ func handleManageWorkItemRelations(ctx context.Context, request mcp.CallToolRequ
 		}
 
 		for i, relation := range *workItem.Relations {
-			if *relation.Rel == azureRelationType {
-				targetUrl := fmt.Sprintf("%s/_apis/wit/workItems/%d", config.OrganizationURL, targetID)
-				if *relation.Url == targetUrl {
-					ops = []webapi.JsonPatchOperation{
-						{
-							Op:   &webapi.OperationValues.Remove,
-							Path: stringPtr(fmt.Sprintf("/relations/%d", i)),
-						},
-					}
-					break
+			if relation.Rel == nil || relation.Url == nil {
+				continue
+			}
+			if strings.EqualFold(*relation.Rel, azureRelationType) && relationTargetMatches(*relation.Url, targetURL) {
+				ops = []webapi.JsonPatchOperation{
+					{
+						Op:   &webapi.OperationValues.Remove,
+						Path: stringPtr(fmt.Sprintf("/relations/%d", i)),
+					},
 				}
+				break
 			}
 		}
 
@@ -650,7 +991,7 @@ func handleManageWorkItemRelations(ctx context.Context, request mcp.CallToolRequ
 		Document: &ops,
 	}
 
-	_, err := workItemClient.UpdateWorkItem(ctx, updateArgs)
+	_, err = workItemClient.UpdateWorkItem(ctx, updateArgs)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update work item relations: %v", err)), nil
 	}
@@ -676,11 +1017,7 @@ func handleGetRelatedWorkItems(ctx context.Context, request mcp.CallToolRequest)
 		return mcp.NewToolResultText("No related items found"), nil
 	}
 
-	relationTypeMap := map[string]string{
-		"parent":   "System.LinkTypes.Hierarchy-Reverse",
-		"children": "System.LinkTypes.Hierarchy-Forward",
-		"related":  "System.LinkTypes.Related",
-	}
+	relationTypeMap := relationAliases
 
 	// Debug information
 	var debugInfo []string
@@ -688,21 +1025,27 @@ func handleGetRelatedWorkItems(ctx context.Context, request mcp.CallToolRequest)
 		relationType, relationTypeMap[relationType]))
 
 	var relatedIds []int
+	var otherLinks []string
 	for _, relation := range *workItem.Relations {
 		debugInfo = append(debugInfo, fmt.Sprintf("Found relation of type: %s", *relation.Rel))
 
 		if relationType == "all" || *relation.Rel == relationTypeMap[relationType] {
-			parts := strings.Split(*relation.Url, "/")
-			if relatedID, err := strconv.Atoi(parts[len(parts)-1]); err == nil {
+			// Only work item links carry an ID; list hyperlinks, artifacts and attachments as they are
+			if relatedID, ok := relatedWorkItemID(*relation.Url); ok {
 				relatedIds = append(relatedIds, relatedID)
+			} else {
+				otherLinks = append(otherLinks, fmt.Sprintf("%s: %s", *relation.Rel, *relation.Url))
 			}
 		}
 	}
 
-	if len(relatedIds) == 0 {
+	if len(relatedIds) == 0 && len(otherLinks) == 0 {
 		return mcp.NewToolResultText(fmt.Sprintf("Debug info:\n%s\n\nNo matching related items found",
 			strings.Join(debugInfo, "\n"))), nil
 	}
+	if len(relatedIds) == 0 {
+		return mcp.NewToolResultText("Other links:\n" + strings.Join(otherLinks, "\n")), nil
+	}
 
 	// Get details of related items
 	relatedItems, err := workItemClient.GetWorkItems(ctx, workitemtracking.GetWorkItemsArgs{
@@ -720,55 +1063,8 @@ func handleGetRelatedWorkItems(ctx context.Context, request mcp.CallToolRequest)
 		result := fmt.Sprintf("ID: %d, Title: %s", *item.Id, title)
 		results = append(results, result)
 	}
-
-	return mcp.NewToolResultText(strings.Join(results, "\n")), nil
-}
-
-// Handler for adding a comment to a work item
-func handleAddWorkItemComment(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	id := int(request.Params.Arguments["id"].(float64))
-	text := request.Params.Arguments["text"].(string)
-
-	// Add comment as a discussion by updating the Discussion field
-	updateArgs := workitemtracking.UpdateWorkItemArgs{
-		Id:      &id,
-		Project: &config.Project,
-		Document: &[]webapi.JsonPatchOperation{
-			{
-				Op:    &webapi.OperationValues.Add,
-				Path:  stringPtr("/fields/System.History"),
-				Value: text,
-			},
-		},
-	}
-
-	workItem, err := workItemClient.UpdateWorkItem(ctx, updateArgs)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to add comment: %v", err)), nil
-	}
-
-	return mcp.NewToolResultText(fmt.Sprintf("Added comment to work item #%d", *workItem.Id)), nil
-}
-
-// Handler for getting work item comments
-func handleGetWorkItemComments(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	id := int(request.Params.Arguments["id"].(float64))
-
-	comments, err := workItemClient.GetComments(ctx, workitemtracking.GetCommentsArgs{
-		Project:    &config.Project,
-		WorkItemId: &id,
-	})
-
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get comments: %v", err)), nil
-	}
-
-	var results []string
-	for _, comment := range *comments.Comments {
-		results = append(results, fmt.Sprintf("Comment by %s at %s:\n%s\n---",
-			*comment.CreatedBy.DisplayName,
-			comment.CreatedDate.String(),
-			*comment.Text))
+	if len(otherLinks) > 0 {
+		results = append(results, "\nOther links:\n"+strings.Join(otherLinks, "\n"))
 	}
 
 	return mcp.NewToolResultText(strings.Join(results, "\n")), nil
@@ -821,6 +1117,7 @@ func handleBatchCreateWorkItems(ctx context.Context, request mcp.CallToolRequest
 		Title       string `json:"title"`
 		Description string `json:"description"`
 		Priority    string `json:"priority,omitempty"`
+		AssignedTo  string `json:"assigned_to,omitempty"`
 	}
 
 	if err := json.Unmarshal([]byte(itemsJSON), &items); err != nil {
@@ -841,7 +1138,7 @@ func handleBatchCreateWorkItems(ctx context.Context, request mcp.CallToolRequest
 				{
 					Op:    &webapi.OperationValues.Add,
 					Path:  stringPtr("/fields/System.Description"),
-					Value: item.Description,
+					Value: markdownToHTML(item.Description),
 				},
 			},
 		}
@@ -855,6 +1152,20 @@ func handleBatchCreateWorkItems(ctx context.Context, request mcp.CallToolRequest
 			createArgs.Document = &doc
 		}
 
+		if item.AssignedTo != "" {
+			resolved, err := resolveAssignedTo(ctx, item.AssignedTo)
+			if err != nil {
+				results = append(results, fmt.Sprintf("Failed to create '%s': %v", item.Title, err))
+				continue
+			}
+			doc := append(*createArgs.Document, webapi.JsonPatchOperation{
+				Op:    &webapi.OperationValues.Add,
+				Path:  stringPtr("/fields/System.AssignedTo"),
+				Value: resolved,
+			})
+			createArgs.Document = &doc
+		}
+
 		workItem, err := workItemClient.CreateWorkItem(ctx, createArgs)
 		if err != nil {
 			results = append(results, fmt.Sprintf("Failed to create '%s': %v", item.Title, err))
@@ -885,6 +1196,7 @@ func handleBatchUpdateWorkItems(ctx context.Context, request mcp.CallToolRequest
 		"Description": "System.Description",
 		"State":       "System.State",
 		"Priority":    "Microsoft.VSTS.Common.Priority",
+		"AssignedTo":  "System.AssignedTo",
 	}
 
 	var results []string
@@ -895,6 +1207,16 @@ func handleBatchUpdateWorkItems(ctx context.Context, request mcp.CallToolRequest
 			continue
 		}
 
+		var value interface{} = convertFieldValue(systemField, update.Value, "markdown")
+		if isAssignedToField(systemField) {
+			resolved, err := resolveAssignedTo(ctx, update.Value)
+			if err != nil {
+				results = append(results, fmt.Sprintf("Failed to update #%d: %v", update.ID, err))
+				continue
+			}
+			value = resolved
+		}
+
 		updateArgs := workitemtracking.UpdateWorkItemArgs{
 			Id:      &update.ID,
 			Project: &config.Project,
@@ -902,7 +1224,7 @@ func handleBatchUpdateWorkItems(ctx context.Context, request mcp.CallToolRequest
 				{
 					Op:    &webapi.OperationValues.Replace,
 					Path:  stringPtr("/fields/" + systemField),
-					Value: update.Value,
+					Value: value,
 				},
 			},
 		}